@@ -3,10 +3,17 @@ package main
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/cache"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/dataexport"
 	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/grpc_client"
 	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/http/handlers"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/jwtkeys"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/mailer"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/ratelimit"
+	"github.com/oziev02/checklist-microservices/internal/api/ports"
 	"log"
 	"os"
+	"time"
 )
 
 func main() {
@@ -21,8 +28,46 @@ func main() {
 	}
 	defer grpcClient.Close()
 
+	// Redis хранит отозванные токены и счётчики неудачных попыток входа. Без него они хранятся
+	// в памяти процесса и действуют только на этом экземпляре API
+	var tokenCache ports.Cache
+	var attempts ports.AttemptStore
+	if redisHost := os.Getenv("REDIS_HOST"); redisHost != "" {
+		redisCache, err := cache.NewRedisCache(redisHost, os.Getenv("REDIS_PORT"), os.Getenv("REDIS_PASSWORD"))
+		if err != nil {
+			log.Fatalf("Failed to connect to Redis: %v", err)
+		}
+		tokenCache = redisCache
+		attempts = redisCache
+	} else {
+		log.Printf("REDIS_HOST is not set, token revocation and login throttling are per instance")
+		tokenCache = cache.NewMemoryCache()
+		attempts = ratelimit.NewMemoryStore()
+	}
+
+	// Ключи подписи access-токенов; без JWT_KEYS_DIR используется HS256 с JWT_SECRET
+	keyRing, err := jwtkeys.NewKeyRingFromEnv(handlers.SignedTokenMaxTTL)
+	if err != nil {
+		log.Fatalf("Failed to load JWT keys: %v", err)
+	}
+	if keyRing != nil {
+		keyRing.StartAutoReload(time.Minute)
+	}
+
+	mail, err := mailer.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to create mailer: %v", err)
+	}
+
+	// Архивы выгрузки данных пользователей хранятся на диске до истечения DATA_EXPORT_TTL
+	exports, err := dataexport.NewStoreFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to create export store: %v", err)
+	}
+	exports.StartCleanup(time.Hour)
+
 	r := gin.Default()
-	handlers.RegisterRoutes(r, grpcClient)
+	handlers.RegisterRoutes(r, grpcClient, tokenCache, attempts, keyRing, mail, exports)
 
 	port := os.Getenv("API_PORT")
 	if port == "" {
@@ -17,6 +17,9 @@ func main() {
 	}
 	defer server.Stop()
 
+	// Удаление аккаунтов по истечении срока отмены
+	server.StartAccountPurge()
+
 	if err := server.Start(); err != nil {
 		log.Fatalf("Failed to start gRPC server: %v", err)
 	}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Как часто MemoryCache удаляет просроченные ключи
+const memorySweepInterval = time.Minute
+
+// MemoryCache — кэш в памяти процесса на случай, когда Redis не настроен. Отзыв токенов
+// и другие отметки действуют только на этом экземпляре API и теряются при перезапуске
+type MemoryCache struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+type memoryEntry struct {
+	value     string
+	expiresAt time.Time // нулевое время — без срока, как Set с нулевым TTL в Redis
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries:   make(map[string]memoryEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// Set сохраняет значение с TTL; нулевой TTL — без срока
+func (m *MemoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.sweep(now)
+
+	m.entries[key] = newMemoryEntry(value, now, ttl)
+	return nil
+}
+
+// Get возвращает значение по ключу или пустую строку, если ключа нет или он истёк
+func (m *MemoryCache) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok || entry.expired(time.Now()) {
+		return "", nil
+	}
+	return entry.value, nil
+}
+
+func (m *MemoryCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+// SetIfAbsent сохраняет значение, если ключа нет или он истёк
+func (m *MemoryCache) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.sweep(now)
+
+	if entry, ok := m.entries[key]; ok && !entry.expired(now) {
+		return false, nil
+	}
+
+	m.entries[key] = newMemoryEntry(value, now, ttl)
+	return true, nil
+}
+
+// sweep удаляет просроченные ключи не чаще memorySweepInterval
+func (m *MemoryCache) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, entry := range m.entries {
+		if entry.expired(now) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+// newMemoryEntry хранит значение в строковом виде, как его вернул бы Redis
+func newMemoryEntry(value interface{}, now time.Time, ttl time.Duration) memoryEntry {
+	entry := memoryEntry{value: fmt.Sprint(value)}
+	if ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+	}
+	return entry
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
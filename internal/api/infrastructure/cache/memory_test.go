@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryCache()
+
+	if err := m.Set(ctx, "number", 42, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := m.Get(ctx, "number"); value != "42" {
+		t.Errorf("Get = %q, want %q", value, "42")
+	}
+	if value, _ := m.Get(ctx, "missing"); value != "" {
+		t.Errorf("Get(missing) = %q, want empty", value)
+	}
+
+	m.Set(ctx, "short", 1, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if value, _ := m.Get(ctx, "short"); value != "" {
+		t.Errorf("Get(expired) = %q, want empty", value)
+	}
+
+	m.Delete(ctx, "number")
+	if value, _ := m.Get(ctx, "number"); value != "" {
+		t.Errorf("Get(deleted) = %q, want empty", value)
+	}
+}
+
+func TestMemoryCacheSetIfAbsent(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryCache()
+
+	if ok, _ := m.SetIfAbsent(ctx, "key", "first", time.Millisecond); !ok {
+		t.Fatal("SetIfAbsent on a new key = false")
+	}
+	if ok, _ := m.SetIfAbsent(ctx, "key", "second", time.Minute); ok {
+		t.Fatal("SetIfAbsent on an existing key = true")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if ok, _ := m.SetIfAbsent(ctx, "key", "third", time.Minute); !ok {
+		t.Fatal("SetIfAbsent on an expired key = false")
+	}
+	if value, _ := m.Get(ctx, "key"); value != "third" {
+		t.Errorf("Get = %q, want %q", value, "third")
+	}
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+type RedisCache struct {
+	client *redis.Client
+}
+
+func NewRedisCache(host, port, password string) (*RedisCache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     host + ":" + port,
+		Password: password,
+		DB:       0, // Используем базу данных по умолчанию
+	})
+
+	ctx := context.Background()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+
+	return &RedisCache{client: client}, nil
+}
+
+// Set сохраняет значение в Redis с TTL
+func (r *RedisCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Get возвращает значение по ключу или пустую строку, если ключа нет
+func (r *RedisCache) Get(ctx context.Context, key string) (string, error) {
+	value, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return value, err
+}
+
+func (r *RedisCache) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, key).Err()
+}
+
+// SetIfAbsent сохраняет значение с TTL, если ключа ещё нет (SET NX)
+func (r *RedisCache) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+// AddAttempt добавляет попытку в sorted set (score — время в миллисекундах) и удаляет
+// попытки, вышедшие за окно. Ключ живёт не дольше окна
+func (r *RedisCache) AddAttempt(ctx context.Context, key string, at time.Time, window time.Duration) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(at.UnixMilli()), Member: uuid.NewString()})
+		pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(at.Add(-window).UnixMilli(), 10))
+		pipe.PExpire(ctx, key, window)
+		return nil
+	})
+	return err
+}
+
+// Attempts возвращает время попыток за последние window по возрастанию
+func (r *RedisCache) Attempts(ctx context.Context, key string, window time.Duration) ([]time.Time, error) {
+	entries, err := r.client.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatInt(time.Now().Add(-window).UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := make([]time.Time, len(entries))
+	for i, entry := range entries {
+		attempts[i] = time.UnixMilli(int64(entry.Score))
+	}
+	return attempts, nil
+}
+
+func (r *RedisCache) ResetAttempts(ctx context.Context, key string) error {
+	return r.client.Del(ctx, key).Err()
+}
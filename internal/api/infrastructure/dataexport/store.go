@@ -0,0 +1,221 @@
+package dataexport
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы выгрузки
+const (
+	StatusPending = "pending" // архив ещё собирается
+	StatusReady   = "ready"
+	StatusFailed  = "failed"
+)
+
+// ErrInProgress — у пользователя уже собирается выгрузка
+var ErrInProgress = errors.New("export is already in progress")
+
+// Export — выгрузка данных пользователя
+type Export struct {
+	ID        string
+	UserID    string
+	Status    string
+	CreatedAt time.Time
+	ExpiresAt time.Time // для готовых: после этого архив удаляется
+}
+
+// Store хранит архивы выгрузок на диске: <dir>/<user_id>/<id>.zip.
+// Пока архив собирается, он пишется в <id>.zip.part; неудачная сборка оставляет <id>.failed.
+// Файлы старше ttl удаляются StartCleanup
+type Store struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewStoreFromEnv создаёт хранилище в DATA_EXPORT_DIR (по умолчанию во временном каталоге)
+// со сроком хранения DATA_EXPORT_TTL (по умолчанию сутки)
+func NewStoreFromEnv() (*Store, error) {
+	dir := os.Getenv("DATA_EXPORT_DIR")
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "checklist-exports")
+	}
+
+	ttl := 24 * time.Hour
+	if value := os.Getenv("DATA_EXPORT_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("Invalid DATA_EXPORT_TTL %q", value)
+		}
+		ttl = parsed
+	}
+
+	return NewStore(dir, ttl)
+}
+
+func NewStore(dir string, ttl time.Duration) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("Failed to create export directory: %v", err)
+	}
+	return &Store{dir: dir, ttl: ttl}, nil
+}
+
+// Job — собираемая выгрузка. Архив пишется в Job, затем Finish публикует его или помечает ошибку
+type Job struct {
+	*os.File
+	Export *Export
+
+	store *Store
+}
+
+// Begin начинает выгрузку. Возвращает ErrInProgress, если у пользователя уже собирается архив
+func (s *Store) Begin(userID string) (*Job, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, fmt.Errorf("Invalid user id: %v", err)
+	}
+
+	userDir := filepath.Join(s.dir, userID)
+	if err := os.MkdirAll(userDir, 0o700); err != nil {
+		return nil, fmt.Errorf("Failed to create export directory: %v", err)
+	}
+	pending, err := filepath.Glob(filepath.Join(userDir, "*.zip.part"))
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) > 0 {
+		return nil, ErrInProgress
+	}
+
+	export := &Export{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Status:    StatusPending,
+		CreatedAt: time.Now(),
+	}
+	file, err := os.OpenFile(s.path(userID, export.ID, ".zip.part"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create export file: %v", err)
+	}
+
+	return &Job{File: file, Export: export, store: s}, nil
+}
+
+// Finish закрывает архив и делает его доступным для скачивания. Если buildErr не nil
+// или архив не удалось сохранить, выгрузка помечается неудачной
+func (j *Job) Finish(buildErr error) error {
+	s := j.store
+	partPath := s.path(j.Export.UserID, j.Export.ID, ".zip.part")
+
+	err := buildErr
+	if closeErr := j.File.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(partPath, s.path(j.Export.UserID, j.Export.ID, ".zip"))
+	}
+	if err == nil {
+		j.Export.Status = StatusReady
+		j.Export.ExpiresAt = time.Now().Add(s.ttl)
+		return nil
+	}
+
+	os.Remove(partPath)
+	if markErr := os.WriteFile(s.path(j.Export.UserID, j.Export.ID, ".failed"), nil, 0o600); markErr != nil {
+		log.Printf("Failed to mark export %s as failed: %v", j.Export.ID, markErr)
+	}
+	j.Export.Status = StatusFailed
+	return err
+}
+
+// Get возвращает выгрузку пользователя или nil, если её нет или срок хранения истёк
+func (s *Store) Get(userID, id string) (*Export, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, nil
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil
+	}
+
+	for _, candidate := range []struct {
+		suffix string
+		status string
+	}{
+		{".zip", StatusReady},
+		{".zip.part", StatusPending},
+		{".failed", StatusFailed},
+	} {
+		info, err := os.Stat(s.path(userID, id, candidate.suffix))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		expiresAt := info.ModTime().Add(s.ttl)
+		if time.Now().After(expiresAt) {
+			return nil, nil
+		}
+		export := &Export{ID: id, UserID: userID, Status: candidate.status, CreatedAt: info.ModTime()}
+		if candidate.status == StatusReady {
+			export.ExpiresAt = expiresAt
+		}
+		return export, nil
+	}
+	return nil, nil
+}
+
+// Path возвращает путь к готовому архиву
+func (s *Store) Path(export *Export) string {
+	return s.path(export.UserID, export.ID, ".zip")
+}
+
+// RemoveUser удаляет все выгрузки пользователя, включая собираемую: её Finish
+// пометит выгрузку неудачной
+func (s *Store) RemoveUser(userID string) error {
+	if _, err := uuid.Parse(userID); err != nil {
+		return fmt.Errorf("Invalid user id: %v", err)
+	}
+	if err := os.RemoveAll(filepath.Join(s.dir, userID)); err != nil {
+		return fmt.Errorf("Failed to remove exports: %v", err)
+	}
+	return nil
+}
+
+// StartCleanup периодически удаляет файлы выгрузок старше срока хранения
+func (s *Store) StartCleanup(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			s.cleanup()
+			<-ticker.C
+		}
+	}()
+}
+
+func (s *Store) cleanup() {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*", "*"))
+	if err != nil {
+		log.Printf("Failed to list exports: %v", err)
+		return
+	}
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil || time.Since(info.ModTime()) < s.ttl {
+			continue
+		}
+		if err := os.Remove(file); err != nil {
+			log.Printf("Failed to remove expired export %s: %v", file, err)
+		}
+	}
+}
+
+func (s *Store) path(userID, id, suffix string) string {
+	return filepath.Join(s.dir, userID, id+suffix)
+}
@@ -0,0 +1,180 @@
+package dataexport
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := NewStore(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestStoreLifecycle(t *testing.T) {
+	store := newTestStore(t)
+	userID := uuid.NewString()
+
+	job, err := store.Begin(userID)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if _, err := store.Begin(userID); !errors.Is(err, ErrInProgress) {
+		t.Fatalf("second Begin error = %v, want ErrInProgress", err)
+	}
+	if export, _ := store.Get(userID, job.Export.ID); export == nil || export.Status != StatusPending {
+		t.Fatalf("Get while building = %+v, want pending", export)
+	}
+
+	if _, err := job.Write([]byte("archive")); err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Finish(nil); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+
+	export, err := store.Get(userID, job.Export.ID)
+	if err != nil || export == nil || export.Status != StatusReady || export.ExpiresAt.IsZero() {
+		t.Fatalf("Get after Finish = %+v, %v; want ready", export, err)
+	}
+	data, err := os.ReadFile(store.Path(export))
+	if err != nil || string(data) != "archive" {
+		t.Fatalf("archive = %q, %v", data, err)
+	}
+
+	// После готовности можно начать новую выгрузку
+	next, err := store.Begin(userID)
+	if err != nil {
+		t.Fatalf("Begin after Finish: %v", err)
+	}
+	next.Finish(nil)
+}
+
+func TestStoreFailedExport(t *testing.T) {
+	store := newTestStore(t)
+	userID := uuid.NewString()
+
+	job, err := store.Begin(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buildErr := errors.New("db unavailable")
+	if err := job.Finish(buildErr); err != buildErr {
+		t.Fatalf("Finish error = %v, want %v", err, buildErr)
+	}
+
+	export, err := store.Get(userID, job.Export.ID)
+	if err != nil || export == nil || export.Status != StatusFailed {
+		t.Fatalf("Get after failure = %+v, %v; want failed", export, err)
+	}
+	if _, err := os.Stat(store.path(userID, job.Export.ID, ".zip.part")); !os.IsNotExist(err) {
+		t.Error("partial archive was not removed")
+	}
+}
+
+func TestStoreGetIsScopedToUser(t *testing.T) {
+	store := newTestStore(t)
+	owner := uuid.NewString()
+
+	job, err := store.Begin(owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job.Finish(nil)
+
+	tests := []struct {
+		name   string
+		userID string
+		id     string
+	}{
+		{"another user", uuid.NewString(), job.Export.ID},
+		{"unknown export", owner, uuid.NewString()},
+		{"path traversal in id", owner, "../" + owner + "/" + job.Export.ID},
+		{"invalid user id", "..", job.Export.ID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if export, err := store.Get(tt.userID, tt.id); export != nil || err != nil {
+				t.Errorf("Get = %+v, %v; want nothing", export, err)
+			}
+		})
+	}
+
+	if _, err := store.Begin("../escape"); err == nil {
+		t.Error("Begin accepted an invalid user id")
+	}
+}
+
+func TestStoreExpiry(t *testing.T) {
+	store := newTestStore(t)
+	userID := uuid.NewString()
+
+	job, err := store.Begin(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job.Finish(nil)
+
+	archive := store.path(userID, job.Export.ID, ".zip")
+	old := time.Now().Add(-2 * store.ttl)
+	if err := os.Chtimes(archive, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if export, _ := store.Get(userID, job.Export.ID); export != nil {
+		t.Errorf("Get for an expired export = %+v, want nil", export)
+	}
+	store.cleanup()
+	if _, err := os.Stat(archive); !os.IsNotExist(err) {
+		t.Error("cleanup kept an expired archive")
+	}
+}
+
+func TestStoreRemoveUser(t *testing.T) {
+	store := newTestStore(t)
+	userID := uuid.NewString()
+	otherID := uuid.NewString()
+
+	ready, err := store.Begin(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ready.Finish(nil); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := store.Begin(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := store.Begin(otherID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Finish(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.RemoveUser(userID); err != nil {
+		t.Fatalf("RemoveUser: %v", err)
+	}
+	if export, _ := store.Get(userID, ready.Export.ID); export != nil {
+		t.Errorf("ready export after RemoveUser = %+v, want nil", export)
+	}
+	// Собираемый архив удалён вместе с остальными, его публикация не удаётся
+	if err := pending.Finish(nil); err == nil {
+		t.Error("Finish after RemoveUser succeeded")
+	}
+	if export, _ := store.Get(userID, pending.Export.ID); export != nil {
+		t.Errorf("pending export after RemoveUser = %+v, want nil", export)
+	}
+	if export, _ := store.Get(otherID, other.Export.ID); export == nil {
+		t.Error("export of another user removed")
+	}
+}
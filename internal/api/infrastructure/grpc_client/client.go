@@ -5,8 +5,10 @@ import (
 	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"log"
 	"os"
+	"time"
 )
 
 // Client представляет gRPC-клиент для взаимодействия с БД-сервисом
@@ -29,19 +31,43 @@ func NewClient() (*Client, error) {
 		log.Fatalf("Failed to connect to DB service: %v", err)
 	}
 
-	// Создаём gRPC-клиент
-	service := api.NewChecklistServiceClient(conn)
+	return NewClientFromConn(conn), nil
+}
 
+// NewClientFromConn создаёт клиент поверх готового соединения с БД-сервисом
+func NewClientFromConn(conn *grpc.ClientConn) *Client {
 	return &Client{
 		conn:    conn,
-		service: service,
-	}, nil
+		service: api.NewChecklistServiceClient(conn),
+	}
 }
 
 func (c *Client) Close() error {
 	return c.conn.Close()
 }
 
+// Ключи метаданных gRPC с ID пользователя, от имени которого выполняется запрос,
+// и ID администратора, выполняющего действие над чужим аккаунтом
+const (
+	userIDMetadataKey  = "x-user-id"
+	adminIDMetadataKey = "x-admin-id"
+)
+
+// AsUser передаёт БД-сервису пользователя, от имени которого выполняется запрос:
+// по нему сервис ограничивает запрос данными этого пользователя. userID берётся из
+// аутентификации запроса (токена, сессии, пройденного шага входа), а не из аргументов
+// вызова, поэтому user_id в запросе, не совпадающий с ним, БД-сервис отклоняет
+func AsUser(ctx context.Context, userID string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, userIDMetadataKey, userID)
+}
+
+// AsAdmin передаёт БД-сервису администратора, выполняющего действие над чужим аккаунтом.
+// Сервис проверяет роль администратора и принимает такие запросы только для вызовов
+// администрирования
+func AsAdmin(ctx context.Context, adminID string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, adminIDMetadataKey, adminID)
+}
+
 func (c *Client) CreateTask(ctx context.Context, title, content, userID string) (*api.TaskResponse, error) {
 	req := &api.TaskRequest{
 		Title:   title,
@@ -58,6 +84,7 @@ func (c *Client) ListTasks(ctx context.Context, userID string) (*api.ListTasksRe
 	return c.service.ListTasks(ctx, req)
 }
 
+// DeleteTask удаляет задачу пользователя запроса (см. AsUser); чужая задача даёт NotFound
 func (c *Client) DeleteTask(ctx context.Context, taskID string) error {
 	req := &api.TaskIDRequest{
 		Id: taskID,
@@ -66,6 +93,7 @@ func (c *Client) DeleteTask(ctx context.Context, taskID string) error {
 	return err
 }
 
+// MarkTaskDone отмечает выполненной задачу пользователя запроса (см. AsUser); чужая задача даёт NotFound
 func (c *Client) MarkTaskDone(ctx context.Context, taskID string) (*api.TaskResponse, error) {
 	req := &api.TaskIDRequest{
 		Id: taskID,
@@ -97,3 +125,347 @@ func (c *Client) GetUserByEmail(ctx context.Context, email string) (*api.UserRes
 	}
 	return c.service.GetUserByEmail(ctx, req)
 }
+
+func (c *Client) GetUserByID(ctx context.Context, userID string) (*api.UserResponse, error) {
+	req := &api.UserIDRequest{
+		UserId: userID,
+	}
+	return c.service.GetUserByID(ctx, req)
+}
+
+func (c *Client) UpdateTwoFA(ctx context.Context, userID string, enabled bool, secret string) (*api.UserResponse, error) {
+	req := &api.UpdateTwoFARequest{
+		UserId:  userID,
+		Enabled: enabled,
+		Secret:  secret,
+	}
+	return c.service.UpdateTwoFA(ctx, req)
+}
+
+func (c *Client) UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	req := &api.UseTOTPStepRequest{
+		UserId: userID,
+		Step:   step,
+	}
+	resp, err := c.service.UseTOTPStep(ctx, req)
+	if err != nil {
+		return false, err
+	}
+	return resp.Used, nil
+}
+
+func (c *Client) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	req := &api.ReplaceRecoveryCodesRequest{
+		UserId:     userID,
+		CodeHashes: codeHashes,
+	}
+	_, err := c.service.ReplaceRecoveryCodes(ctx, req)
+	return err
+}
+
+func (c *Client) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	req := &api.UseRecoveryCodeRequest{
+		UserId:   userID,
+		CodeHash: codeHash,
+	}
+	resp, err := c.service.UseRecoveryCode(ctx, req)
+	if err != nil {
+		return false, err
+	}
+	return resp.Used, nil
+}
+
+func (c *Client) CreateRefreshFamily(ctx context.Context, familyID, userID, jti string, expiresAt time.Time, deviceName, userAgent, ip string) error {
+	req := &api.CreateRefreshFamilyRequest{
+		FamilyId:   familyID,
+		UserId:     userID,
+		Jti:        jti,
+		ExpiresAt:  expiresAt.Unix(),
+		DeviceName: deviceName,
+		UserAgent:  userAgent,
+		Ip:         ip,
+	}
+	_, err := c.service.CreateRefreshFamily(ctx, req)
+	return err
+}
+
+func (c *Client) RotateRefreshToken(ctx context.Context, familyID, oldJTI, newJTI string, expiresAt time.Time) (*api.RotateRefreshTokenResponse, error) {
+	req := &api.RotateRefreshTokenRequest{
+		FamilyId:  familyID,
+		OldJti:    oldJTI,
+		NewJti:    newJTI,
+		ExpiresAt: expiresAt.Unix(),
+	}
+	return c.service.RotateRefreshToken(ctx, req)
+}
+
+func (c *Client) RevokeRefreshFamily(ctx context.Context, userID, familyID string) error {
+	req := &api.RevokeRefreshFamilyRequest{
+		UserId:   userID,
+		FamilyId: familyID,
+	}
+	_, err := c.service.RevokeRefreshFamily(ctx, req)
+	return err
+}
+
+func (c *Client) RevokeUserRefreshFamilies(ctx context.Context, userID string) error {
+	req := &api.UserIDRequest{
+		UserId: userID,
+	}
+	_, err := c.service.RevokeUserRefreshFamilies(ctx, req)
+	return err
+}
+
+func (c *Client) ListSessions(ctx context.Context, userID string) (*api.ListSessionsResponse, error) {
+	req := &api.UserIDRequest{
+		UserId: userID,
+	}
+	return c.service.ListSessions(ctx, req)
+}
+
+func (c *Client) SignInWithIdentity(ctx context.Context, provider, subject, email string, emailVerified bool) (*api.UserResponse, error) {
+	req := &api.IdentityRequest{
+		Provider:      provider,
+		Subject:       subject,
+		Email:         email,
+		EmailVerified: emailVerified,
+	}
+	return c.service.SignInWithIdentity(ctx, req)
+}
+
+func (c *Client) CreateEmailToken(ctx context.Context, userID, purpose, tokenHash string, expiresAt time.Time) error {
+	req := &api.CreateEmailTokenRequest{
+		UserId:    userID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt.Unix(),
+	}
+	_, err := c.service.CreateEmailToken(ctx, req)
+	return err
+}
+
+// UpdatePassword меняет хэш пароля пользователя и отзывает все его семейства refresh-токенов,
+// кроме keepSessionID (текущей сессии)
+func (c *Client) UpdatePassword(ctx context.Context, userID, password, keepSessionID string) error {
+	req := &api.UpdatePasswordRequest{
+		UserId:        userID,
+		Password:      password,
+		KeepSessionId: keepSessionID,
+	}
+	_, err := c.service.UpdatePassword(ctx, req)
+	return err
+}
+
+func (c *Client) CreatePersonalAccessToken(ctx context.Context, userID, name, tokenHash string, scopes []string, expiresAt time.Time) (*api.PersonalAccessTokenResponse, error) {
+	req := &api.CreatePersonalAccessTokenRequest{
+		UserId:    userID,
+		Name:      name,
+		TokenHash: tokenHash,
+		Scopes:    scopes,
+	}
+	if !expiresAt.IsZero() {
+		req.ExpiresAt = expiresAt.Unix()
+	}
+	return c.service.CreatePersonalAccessToken(ctx, req)
+}
+
+func (c *Client) ListPersonalAccessTokens(ctx context.Context, userID string) (*api.ListPersonalAccessTokensResponse, error) {
+	req := &api.UserIDRequest{
+		UserId: userID,
+	}
+	return c.service.ListPersonalAccessTokens(ctx, req)
+}
+
+func (c *Client) RevokePersonalAccessToken(ctx context.Context, userID, tokenID string) error {
+	req := &api.RevokePersonalAccessTokenRequest{
+		UserId:  userID,
+		TokenId: tokenID,
+	}
+	_, err := c.service.RevokePersonalAccessToken(ctx, req)
+	return err
+}
+
+func (c *Client) AuthenticatePersonalAccessToken(ctx context.Context, tokenHash string, recordUse bool) (*api.PersonalAccessTokenResponse, error) {
+	req := &api.AuthenticatePersonalAccessTokenRequest{
+		TokenHash: tokenHash,
+		RecordUse: recordUse,
+	}
+	return c.service.AuthenticatePersonalAccessToken(ctx, req)
+}
+
+func (c *Client) ScheduleAccountDeletion(ctx context.Context, userID string, deleteAt time.Time) error {
+	req := &api.ScheduleAccountDeletionRequest{
+		UserId:   userID,
+		DeleteAt: deleteAt.Unix(),
+	}
+	_, err := c.service.ScheduleAccountDeletion(ctx, req)
+	return err
+}
+
+func (c *Client) CancelAccountDeletion(ctx context.Context, userID string) (bool, error) {
+	req := &api.UserIDRequest{
+		UserId: userID,
+	}
+	resp, err := c.service.CancelAccountDeletion(ctx, req)
+	if err != nil {
+		return false, err
+	}
+	return resp.Cancelled, nil
+}
+
+func (c *Client) ListUsers(ctx context.Context, query string, offset, limit int) (*api.ListUsersResponse, error) {
+	req := &api.ListUsersRequest{
+		Query:  query,
+		Offset: int32(offset),
+		Limit:  int32(limit),
+	}
+	return c.service.ListUsers(ctx, req)
+}
+
+func (c *Client) SetUserStatus(ctx context.Context, userID, status string) (*api.UserResponse, error) {
+	req := &api.SetUserStatusRequest{
+		UserId: userID,
+		Status: status,
+	}
+	return c.service.SetUserStatus(ctx, req)
+}
+
+func (c *Client) ResetTwoFactor(ctx context.Context, userID string) error {
+	req := &api.UserIDRequest{
+		UserId: userID,
+	}
+	_, err := c.service.ResetTwoFactor(ctx, req)
+	return err
+}
+
+func (c *Client) DeleteUser(ctx context.Context, userID string) error {
+	req := &api.UserIDRequest{
+		UserId: userID,
+	}
+	_, err := c.service.DeleteUser(ctx, req)
+	return err
+}
+
+func (c *Client) RecordAuditEvent(ctx context.Context, actorID, action, targetID, ip, userAgent, result string, details map[string]string) error {
+	req := &api.AuditEventRequest{
+		ActorId:   actorID,
+		Action:    action,
+		TargetId:  targetID,
+		Ip:        ip,
+		UserAgent: userAgent,
+		Result:    result,
+		Details:   details,
+	}
+	_, err := c.service.RecordAuditEvent(ctx, req)
+	return err
+}
+
+func (c *Client) ListAuditEvents(ctx context.Context, req *api.ListAuditEventsRequest) (*api.ListAuditEventsResponse, error) {
+	return c.service.ListAuditEvents(ctx, req)
+}
+
+// ListUserAuditEvents возвращает записи журнала аудита об аккаунте userID
+func (c *Client) ListUserAuditEvents(ctx context.Context, userID string, offset, limit int) (*api.ListAuditEventsResponse, error) {
+	req := &api.ListAuditEventsRequest{
+		TargetId: userID,
+		Offset:   int32(offset),
+		Limit:    int32(limit),
+	}
+	return c.service.ListAuditEvents(ctx, req)
+}
+
+// ExportUserData открывает поток с файлами выгрузки данных пользователя
+func (c *Client) ExportUserData(ctx context.Context, userID string) (api.ChecklistService_ExportUserDataClient, error) {
+	req := &api.UserIDRequest{
+		UserId: userID,
+	}
+	return c.service.ExportUserData(ctx, req)
+}
+
+func (c *Client) CreateWebAuthnCredential(ctx context.Context, userID, credentialID, name string, data []byte) (*api.WebAuthnCredentialResponse, error) {
+	req := &api.CreateWebAuthnCredentialRequest{
+		UserId:       userID,
+		CredentialId: credentialID,
+		Name:         name,
+		Data:         data,
+	}
+	return c.service.CreateWebAuthnCredential(ctx, req)
+}
+
+func (c *Client) ListWebAuthnCredentials(ctx context.Context, userID string) (*api.ListWebAuthnCredentialsResponse, error) {
+	req := &api.UserIDRequest{
+		UserId: userID,
+	}
+	return c.service.ListWebAuthnCredentials(ctx, req)
+}
+
+func (c *Client) GetWebAuthnCredential(ctx context.Context, credentialID string) (*api.WebAuthnCredentialResponse, error) {
+	req := &api.WebAuthnCredentialIDRequest{
+		CredentialId: credentialID,
+	}
+	return c.service.GetWebAuthnCredential(ctx, req)
+}
+
+func (c *Client) UpdateWebAuthnCredentialUsage(ctx context.Context, userID, credentialID string, data []byte) error {
+	req := &api.UpdateWebAuthnCredentialUsageRequest{
+		UserId:       userID,
+		CredentialId: credentialID,
+		Data:         data,
+	}
+	_, err := c.service.UpdateWebAuthnCredentialUsage(ctx, req)
+	return err
+}
+
+func (c *Client) DeleteWebAuthnCredential(ctx context.Context, userID, credentialID string) error {
+	req := &api.DeleteWebAuthnCredentialRequest{
+		UserId:       userID,
+		CredentialId: credentialID,
+	}
+	_, err := c.service.DeleteWebAuthnCredential(ctx, req)
+	return err
+}
+
+func (c *Client) RehashPassword(ctx context.Context, userID, oldPassword, newPassword string) error {
+	req := &api.RehashPasswordRequest{
+		UserId:      userID,
+		OldPassword: oldPassword,
+		NewPassword: newPassword,
+	}
+	_, err := c.service.RehashPassword(ctx, req)
+	return err
+}
+
+func (c *Client) VerifyEmail(ctx context.Context, tokenHash string) (string, error) {
+	req := &api.VerifyEmailRequest{TokenHash: tokenHash}
+	resp, err := c.service.VerifyEmail(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return resp.UserId, nil
+}
+
+func (c *Client) ConsumeMagicLink(ctx context.Context, tokenHash string) (*api.UserResponse, error) {
+	req := &api.MagicLinkRequest{TokenHash: tokenHash}
+	return c.service.ConsumeMagicLink(ctx, req)
+}
+
+func (c *Client) GetUserByEmailToken(ctx context.Context, purpose, tokenHash string) (*api.UserResponse, error) {
+	req := &api.EmailTokenRequest{
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+	}
+	return c.service.GetUserByEmailToken(ctx, req)
+}
+
+// ResetPassword устанавливает новый пароль (хэш) по токену сброса и возвращает ID пользователя
+func (c *Client) ResetPassword(ctx context.Context, tokenHash, password string) (string, error) {
+	req := &api.ResetPasswordRequest{
+		TokenHash: tokenHash,
+		Password:  password,
+	}
+	resp, err := c.service.ResetPassword(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return resp.UserId, nil
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Срок, в течение которого удаление аккаунта можно отменить входом
+const defaultAccountDeletionGracePeriod = 30 * 24 * time.Hour
+
+// accountDeletionGracePeriod читает ACCOUNT_DELETION_GRACE_PERIOD (например, 720h)
+func accountDeletionGracePeriod() time.Duration {
+	value := os.Getenv("ACCOUNT_DELETION_GRACE_PERIOD")
+	if value == "" {
+		return defaultAccountDeletionGracePeriod
+	}
+	gracePeriod, err := time.ParseDuration(value)
+	if err != nil || gracePeriod < 0 {
+		log.Printf("Invalid ACCOUNT_DELETION_GRACE_PERIOD %q, using %s", value, defaultAccountDeletionGracePeriod)
+		return defaultAccountDeletionGracePeriod
+	}
+	return gracePeriod
+}
+
+// deleteAccountHandler планирует удаление аккаунта. Требует текущий пароль и TOTP-код,
+// если включена 2FA. Все сессии завершаются; вход до истечения срока отменяет удаление,
+// после него БД-сервис удаляет аккаунт со всеми данными
+func (h *AuthHandler) deleteAccountHandler(c *gin.Context) {
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		Code            string `json:"code"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.CurrentPassword == "" {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	userResp, err := h.grpcClient.GetUserByID(userContext(c), userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	if !h.reauthenticate(c, userResp, req.CurrentPassword, req.Code) {
+		return
+	}
+
+	deleteAt := time.Now().Add(accountDeletionGracePeriod())
+	if err := h.grpcClient.ScheduleAccountDeletion(userContext(c), userID, deleteAt); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to schedule account deletion"})
+		return
+	}
+	h.forgetUserStatus(c.Request.Context(), userID)
+	// Архивы выгрузок хранятся API-сервисом, очистка в БД-сервисе их не затрагивает.
+	// Отмена удаления их не вернёт, но новую выгрузку можно запросить после входа
+	h.removeExports(userID)
+	recordOwnAudit(h.grpcClient, c, auditActionDeletionSchedule, true)
+
+	// Refresh-токены отозваны вместе с планированием удаления, остаются access-токены
+	if err := h.revokeUserAccessTokens(c.Request.Context(), userID); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+
+	c.JSON(202, gin.H{
+		"message":               "Account deletion scheduled; log in before the deadline to cancel it",
+		"deletion_scheduled_at": deleteAt.UTC(),
+	})
+}
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/http/middleware"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Размер страницы в списках администратора и истории аккаунта
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// adminListUsersHandler ищет пользователей по части email с постраничным выводом.
+// Поиск попадает в журнал аудита вместе с запросом и страницей
+func (h *AuthHandler) adminListUsersHandler(c *gin.Context) {
+	page, pageSize, ok := pageParams(c)
+	if !ok {
+		return
+	}
+
+	query := c.Query("query")
+	details := map[string]string{
+		"query":     query,
+		"page":      strconv.Itoa(page),
+		"page_size": strconv.Itoa(pageSize),
+	}
+	resp, err := h.grpcClient.ListUsers(adminContext(c), query, (page-1)*pageSize, pageSize)
+	if err != nil {
+		recordAuditDetails(h.grpcClient, c, auditActor(c), auditActionAdminListUsers, "", false, details)
+		c.JSON(500, gin.H{"error": "Failed to list users"})
+		return
+	}
+	recordAuditDetails(h.grpcClient, c, auditActor(c), auditActionAdminListUsers, "", true, details)
+
+	users := make([]gin.H, 0, len(resp.Users))
+	for _, user := range resp.Users {
+		users = append(users, adminUserJSON(user))
+	}
+
+	c.JSON(200, gin.H{
+		"users":     users,
+		"total":     resp.Total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// adminGetUserHandler возвращает пользователя и его активные сессии
+func (h *AuthHandler) adminGetUserHandler(c *gin.Context) {
+	userResp, ok := h.adminTargetUser(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.grpcClient.ListSessions(adminContext(c), userResp.Id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get sessions"})
+		return
+	}
+
+	body := adminUserJSON(userResp)
+	sessionList := make([]gin.H, 0, len(sessions.Sessions))
+	for _, session := range sessions.Sessions {
+		sessionList = append(sessionList, gin.H{
+			"id":           session.Id,
+			"device_name":  session.DeviceName,
+			"user_agent":   session.UserAgent,
+			"ip":           session.Ip,
+			"created_at":   time.Unix(session.CreatedAt, 0).UTC(),
+			"last_seen_at": time.Unix(session.LastSeenAt, 0).UTC(),
+		})
+	}
+	body["sessions"] = sessionList
+	recordAudit(h.grpcClient, c, auditActionAdminViewUser, userResp.Id, true)
+
+	c.JSON(200, body)
+}
+
+// adminDisableUserHandler отключает аккаунт и завершает все его сессии
+func (h *AuthHandler) adminDisableUserHandler(c *gin.Context) {
+	h.adminSetUserStatus(c, userStatusSuspended, auditActionAdminDisableUser)
+}
+
+// adminEnableUserHandler включает отключённый аккаунт
+func (h *AuthHandler) adminEnableUserHandler(c *gin.Context) {
+	h.adminSetUserStatus(c, userStatusActive, auditActionAdminEnableUser)
+}
+
+func (h *AuthHandler) adminSetUserStatus(c *gin.Context, userStatus, action string) {
+	targetID := c.Param("id")
+	if !h.adminCheckNotSelf(c, action, targetID) {
+		return
+	}
+
+	userResp, err := h.grpcClient.SetUserStatus(adminContext(c), targetID, userStatus)
+	if status.Code(err) == codes.NotFound {
+		recordAudit(h.grpcClient, c, action, targetID, false)
+		c.JSON(404, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		recordAudit(h.grpcClient, c, action, targetID, false)
+		c.JSON(500, gin.H{"error": "Failed to update user status"})
+		return
+	}
+
+	h.forgetUserStatus(c.Request.Context(), targetID)
+
+	if userStatus == userStatusSuspended {
+		if err := h.revokeAllSessions(adminContext(c), targetID); err != nil {
+			recordAudit(h.grpcClient, c, action, targetID, false)
+			c.JSON(500, gin.H{"error": "Failed to revoke sessions"})
+			return
+		}
+	}
+	recordAudit(h.grpcClient, c, action, targetID, true)
+
+	c.JSON(200, adminUserJSON(userResp))
+}
+
+// adminLogoutUserHandler завершает все сессии пользователя
+func (h *AuthHandler) adminLogoutUserHandler(c *gin.Context) {
+	userResp, ok := h.adminTargetUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.revokeAllSessions(adminContext(c), userResp.Id); err != nil {
+		recordAudit(h.grpcClient, c, auditActionAdminLogoutUser, userResp.Id, false)
+		c.JSON(500, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+	recordAudit(h.grpcClient, c, auditActionAdminLogoutUser, userResp.Id, true)
+
+	c.Status(204)
+}
+
+// adminImpersonateUserHandler выдаёт администратору короткоживущий access-токен пользователя,
+// чтобы увидеть сервис его глазами. Токен несёт claim act с ID администратора, не обновляется
+// и не даёт менять пароль, 2FA и удалять аккаунт. Администраторов имперсонировать нельзя
+func (h *AuthHandler) adminImpersonateUserHandler(c *gin.Context) {
+	if !h.adminCheckNotSelf(c, auditActionAdminImpersonate, c.Param("id")) {
+		return
+	}
+	userResp, ok := h.adminTargetUser(c)
+	if !ok {
+		return
+	}
+
+	if userResp.Role == roleAdmin {
+		recordAudit(h.grpcClient, c, auditActionAdminImpersonate, userResp.Id, false)
+		c.JSON(403, gin.H{"error": "Administrators cannot be impersonated"})
+		return
+	}
+	if userResp.Status != userStatusActive {
+		recordAudit(h.grpcClient, c, auditActionAdminImpersonate, userResp.Id, false)
+		c.JSON(409, gin.H{"error": accountStatusError(userResp.Status)})
+		return
+	}
+
+	// Сессия имперсонации запоминается, чтобы её можно было отозвать до истечения токена
+	sessionID := uuid.New().String()
+	if err := h.cache.Set(c.Request.Context(), impersonationKeyPrefix+sessionID, userResp.Id, impersonationTokenTTL); err != nil {
+		recordAudit(h.grpcClient, c, auditActionAdminImpersonate, userResp.Id, false)
+		c.JSON(500, gin.H{"error": "Failed to create impersonation session"})
+		return
+	}
+	accessToken, err := h.generateImpersonationToken(userResp.Id, userResp.Role, c.GetString("user_id"), sessionID)
+	if err != nil {
+		recordAudit(h.grpcClient, c, auditActionAdminImpersonate, userResp.Id, false)
+		c.JSON(500, gin.H{"error": "Failed to generate access token"})
+		return
+	}
+	recordAuditDetails(h.grpcClient, c, auditActor(c), auditActionAdminImpersonate, userResp.Id, true,
+		map[string]string{"session_id": sessionID})
+
+	c.JSON(200, gin.H{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(impersonationTokenTTL.Seconds()),
+		"user_id":      userResp.Id,
+		"session_id":   sessionID,
+	})
+}
+
+// adminRevokeImpersonationHandler досрочно завершает сессию имперсонации: все её токены
+// перестают приниматься. Сессии самого пользователя не затрагиваются
+func (h *AuthHandler) adminRevokeImpersonationHandler(c *gin.Context) {
+	sessionID := c.Param("sid")
+	if _, err := uuid.Parse(sessionID); err != nil {
+		c.JSON(404, gin.H{"error": "Impersonation session not found"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	userID, err := h.cache.Get(ctx, impersonationKeyPrefix+sessionID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get impersonation session"})
+		return
+	}
+	if userID == "" {
+		// Неизвестная или уже истёкшая сессия
+		c.JSON(404, gin.H{"error": "Impersonation session not found"})
+		return
+	}
+
+	details := map[string]string{"session_id": sessionID}
+	if err := h.cache.Set(ctx, revokedSessionKeyPrefix+sessionID, 1, impersonationTokenTTL); err != nil {
+		recordAuditDetails(h.grpcClient, c, auditActor(c), auditActionAdminEndImpersonate, userID, false, details)
+		c.JSON(500, gin.H{"error": "Failed to revoke impersonation session"})
+		return
+	}
+	if err := h.cache.Delete(ctx, impersonationKeyPrefix+sessionID); err != nil {
+		log.Printf("Failed to forget impersonation session %s: %v", sessionID, err)
+	}
+	recordAuditDetails(h.grpcClient, c, auditActor(c), auditActionAdminEndImpersonate, userID, true, details)
+
+	c.Status(204)
+}
+
+// recordImpersonatedRequest записывает в журнал аудита запрос администратора от имени
+// пользователя: метод, путь и статус ответа
+func (h *AuthHandler) recordImpersonatedRequest(c *gin.Context, principal *middleware.Principal) {
+	details := map[string]string{
+		"method": c.Request.Method,
+		"path":   c.Request.URL.Path,
+		"status": strconv.Itoa(c.Writer.Status()),
+	}
+	recordAuditDetails(h.grpcClient, c, principal.ActorID, auditActionImpersonatedRequest, principal.UserID,
+		c.Writer.Status() < 400, details)
+}
+
+// adminResetTwoFAHandler отключает 2FA пользователя, потерявшего доступ ко второму фактору:
+// TOTP, коды восстановления и passkey
+func (h *AuthHandler) adminResetTwoFAHandler(c *gin.Context) {
+	targetID := c.Param("id")
+	if _, err := uuid.Parse(targetID); err != nil {
+		c.JSON(404, gin.H{"error": "User not found"})
+		return
+	}
+
+	err := h.grpcClient.ResetTwoFactor(adminContext(c), targetID)
+	if status.Code(err) == codes.NotFound {
+		recordAudit(h.grpcClient, c, auditActionAdminReset2FA, targetID, false)
+		c.JSON(404, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		recordAudit(h.grpcClient, c, auditActionAdminReset2FA, targetID, false)
+		c.JSON(500, gin.H{"error": "Failed to reset 2FA"})
+		return
+	}
+	recordAudit(h.grpcClient, c, auditActionAdminReset2FA, targetID, true)
+
+	c.Status(204)
+}
+
+// adminDeleteUserHandler сразу удаляет пользователя со всеми данными, без срока отмены
+func (h *AuthHandler) adminDeleteUserHandler(c *gin.Context) {
+	targetID := c.Param("id")
+	if !h.adminCheckNotSelf(c, auditActionAdminDeleteUser, targetID) {
+		return
+	}
+
+	err := h.grpcClient.DeleteUser(adminContext(c), targetID)
+	if status.Code(err) == codes.NotFound {
+		recordAudit(h.grpcClient, c, auditActionAdminDeleteUser, targetID, false)
+		c.JSON(404, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		recordAudit(h.grpcClient, c, auditActionAdminDeleteUser, targetID, false)
+		c.JSON(500, gin.H{"error": "Failed to delete user"})
+		return
+	}
+
+	h.forgetUserStatus(c.Request.Context(), targetID)
+	h.removeExports(targetID)
+
+	// Сессии удалены вместе с пользователем, остаются выпущенные access-токены
+	if err := h.revokeUserAccessTokens(c.Request.Context(), targetID); err != nil {
+		log.Printf("Failed to revoke access tokens of deleted user %s: %v", targetID, err)
+	}
+	recordAudit(h.grpcClient, c, auditActionAdminDeleteUser, targetID, true)
+
+	c.Status(204)
+}
+
+// adminTargetUser загружает пользователя из пути запроса; при ошибке отвечает клиенту
+func (h *AuthHandler) adminTargetUser(c *gin.Context) (*api.UserResponse, bool) {
+	if _, err := uuid.Parse(c.Param("id")); err != nil {
+		c.JSON(404, gin.H{"error": "User not found"})
+		return nil, false
+	}
+
+	userResp, err := h.grpcClient.GetUserByID(adminContext(c), c.Param("id"))
+	if status.Code(err) == codes.NotFound {
+		c.JSON(404, gin.H{"error": "User not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get user"})
+		return nil, false
+	}
+	return userResp, true
+}
+
+// adminCheckNotSelf запрещает администратору отключить, удалить или имперсонировать собственный аккаунт
+func (h *AuthHandler) adminCheckNotSelf(c *gin.Context, action, targetID string) bool {
+	if _, err := uuid.Parse(targetID); err != nil {
+		c.JSON(404, gin.H{"error": "User not found"})
+		return false
+	}
+	if targetID == c.GetString("user_id") {
+		recordAudit(h.grpcClient, c, action, targetID, false)
+		c.JSON(400, gin.H{"error": "Administrators cannot perform this action on their own account"})
+		return false
+	}
+	return true
+}
+
+// pageParams читает номер страницы page (с 1) и page_size из запроса; при ошибке отвечает 400
+func pageParams(c *gin.Context) (int, int, bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(400, gin.H{"error": "page must be a positive integer"})
+		return 0, 0, false
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(defaultPageSize)))
+	if err != nil || pageSize < 1 || pageSize > maxPageSize {
+		c.JSON(400, gin.H{"error": "page_size must be between 1 and 100"})
+		return 0, 0, false
+	}
+	// Смещение (page-1)*pageSize передаётся в БД-сервис как int32
+	if page-1 > math.MaxInt32/pageSize {
+		c.JSON(400, gin.H{"error": "page is too large"})
+		return 0, 0, false
+	}
+	return page, pageSize, true
+}
+
+// adminUserJSON преобразует пользователя в ответ API без хэша пароля и секрета 2FA
+func adminUserJSON(user *api.UserResponse) gin.H {
+	body := gin.H{
+		"id":                    user.Id,
+		"email":                 user.Email,
+		"email_verified":        user.EmailVerified,
+		"role":                  user.Role,
+		"status":                user.Status,
+		"twofa_enabled":         user.TwofaEnabled,
+		"deletion_scheduled_at": nil,
+	}
+	if user.DeletionScheduledAt != 0 {
+		body["deletion_scheduled_at"] = time.Unix(user.DeletionScheduledAt, 0).UTC()
+	}
+	return body
+}
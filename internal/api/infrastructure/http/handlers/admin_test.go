@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/cache"
+)
+
+func TestImpersonationSessionRevocation(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	h := &AuthHandler{cache: cache.NewMemoryCache()}
+	ctx := context.Background()
+
+	tokenString, err := h.generateImpersonationToken("user-1", roleUser, "admin-1", "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Parse(tokenString, h.accessTokenKeyfunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	if claims["sid"] != "session-1" {
+		t.Fatalf("sid = %v, want session-1", claims["sid"])
+	}
+
+	if revoked, err := h.isAccessTokenRevoked(ctx, claims); err != nil || revoked {
+		t.Fatalf("fresh token revoked = %v, err = %v", revoked, err)
+	}
+
+	// Так сессию имперсонации отзывает adminRevokeImpersonationHandler
+	if err := h.cache.Set(ctx, revokedSessionKeyPrefix+"session-1", 1, impersonationTokenTTL); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, err := h.isAccessTokenRevoked(ctx, claims); err != nil || !revoked {
+		t.Errorf("revoked token revoked = %v, err = %v", revoked, err)
+	}
+}
+
+func TestPageParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		query      string
+		wantOK     bool
+		wantOffset int
+	}{
+		{"", true, 0},
+		{"page=3&page_size=10", true, 20},
+		{"page=0", false, 0},
+		{"page=2&page_size=101", false, 0},
+		{"page=21474837&page_size=100", true, 2147483600},
+		// (page-1)*page_size не помещается в int32 и стал бы отрицательным смещением
+		{"page=21474838&page_size=100", false, 0},
+		{"page=9223372036854775807", false, 0},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/admin/users?"+tt.query, nil)
+
+		page, pageSize, ok := pageParams(c)
+		if ok != tt.wantOK {
+			t.Errorf("%q: ok = %v, want %v", tt.query, ok, tt.wantOK)
+			continue
+		}
+		if !ok && w.Code != 400 {
+			t.Errorf("%q: status %d, want 400", tt.query, w.Code)
+		}
+		if ok && (page-1)*pageSize != tt.wantOffset {
+			t.Errorf("%q: offset %d, want %d", tt.query, (page-1)*pageSize, tt.wantOffset)
+		}
+	}
+}
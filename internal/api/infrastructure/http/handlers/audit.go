@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/grpc_client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Действия в журнале аудита
+const (
+	auditActionLogin               = "auth.login"
+	auditActionLogout              = "auth.logout"
+	auditActionLogoutAll           = "auth.logout_all"
+	auditActionReauthenticate      = "auth.reauthenticate"
+	auditActionPasswordChange      = "account.password.change"
+	auditActionPasswordReset       = "account.password.reset"
+	auditActionEmailVerify         = "account.email.verify"
+	auditActionTwoFAEnable         = "account.2fa.enable"
+	auditActionRecoveryCodesRegen  = "account.2fa.recovery_codes"
+	auditActionPasskeyAdd          = "account.passkey.add"
+	auditActionPasskeyRemove       = "account.passkey.remove"
+	auditActionSessionRevoke       = "account.session.revoke"
+	auditActionTokenCreate         = "account.token.create"
+	auditActionTokenRevoke         = "account.token.revoke"
+	auditActionDeletionSchedule    = "account.deletion.schedule"
+	auditActionDeletionCancel      = "account.deletion.cancel"
+	auditActionDataExport          = "account.export"
+	auditActionProfileUpdate       = "profile.update"
+	auditActionAdminListUsers      = "admin.user.list"
+	auditActionAdminViewUser       = "admin.user.view"
+	auditActionAdminDisableUser    = "admin.user.disable"
+	auditActionAdminEnableUser     = "admin.user.enable"
+	auditActionAdminLogoutUser     = "admin.user.logout"
+	auditActionAdminImpersonate    = "admin.user.impersonate"
+	auditActionAdminEndImpersonate = "admin.user.impersonate_revoke"
+	auditActionImpersonatedRequest = "admin.impersonated_request"
+	auditActionAdminReset2FA       = "admin.user.reset_2fa"
+	auditActionAdminDeleteUser     = "admin.user.delete"
+)
+
+// Результаты действий
+const (
+	auditResultSuccess = "success"
+	auditResultFailure = "failure"
+)
+
+// recordAudit записывает действие текущего пользователя над targetID в журнал аудита
+func recordAudit(grpcClient *grpc_client.Client, c *gin.Context, action, targetID string, success bool) {
+	recordAuditAs(grpcClient, c, auditActor(c), action, targetID, success)
+}
+
+// recordOwnAudit записывает действие над собственным аккаунтом текущего пользователя:
+// такие записи он видит в истории /account/activity
+func recordOwnAudit(grpcClient *grpc_client.Client, c *gin.Context, action string, success bool) {
+	recordAuditAs(grpcClient, c, auditActor(c), action, c.GetString("user_id"), success)
+}
+
+// auditActor — кто выполняет запрос: администратор при имперсонации, иначе сам пользователь
+func auditActor(c *gin.Context) string {
+	if actorID := c.GetString("actor_id"); actorID != "" {
+		return actorID
+	}
+	return c.GetString("user_id")
+}
+
+// recordAuditAs записывает действие actorID над targetID. Пустой actorID — действие
+// без аутентифицированного пользователя, например неудачный вход.
+// Ошибка записи не отменяет уже выполненное действие, но попадает в лог
+func recordAuditAs(grpcClient *grpc_client.Client, c *gin.Context, actorID, action, targetID string, success bool) {
+	recordAuditDetails(grpcClient, c, actorID, action, targetID, success, nil)
+}
+
+// recordAuditDetails записывает действие вместе с его параметрами, например фильтрами поиска
+func recordAuditDetails(grpcClient *grpc_client.Client, c *gin.Context, actorID, action, targetID string, success bool, details map[string]string) {
+	result := auditResultSuccess
+	if !success {
+		result = auditResultFailure
+	}
+
+	err := grpcClient.RecordAuditEvent(context.Background(), actorID, action, targetID,
+		c.ClientIP(), c.Request.UserAgent(), result, details)
+	if err != nil {
+		log.Printf("Failed to record audit event %s: %v", action, err)
+	}
+}
+
+// accountActivityHandler возвращает историю безопасности текущего пользователя:
+// входы, смены пароля и 2FA, изменения профиля и действия администраторов с аккаунтом
+func (h *AuthHandler) accountActivityHandler(c *gin.Context) {
+	page, pageSize, ok := pageParams(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.grpcClient.ListUserAuditEvents(userContext(c), c.GetString("user_id"), (page-1)*pageSize, pageSize)
+	h.sendAuditEvents(c, resp, err, page, pageSize)
+}
+
+// adminListAuditEventsHandler ищет записи журнала аудита по фильтрам
+func (h *AuthHandler) adminListAuditEventsHandler(c *gin.Context) {
+	page, pageSize, ok := pageParams(c)
+	if !ok {
+		return
+	}
+
+	req := &api.ListAuditEventsRequest{
+		ActorId:  c.Query("actor_id"),
+		TargetId: c.Query("target_id"),
+		Action:   c.Query("action"),
+		Result:   c.Query("result"),
+		Offset:   int32((page - 1) * pageSize),
+		Limit:    int32(pageSize),
+	}
+	for _, bound := range []struct {
+		param string
+		value *int64
+	}{{"since", &req.Since}, {"until", &req.Until}} {
+		value := c.Query(bound.param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(400, gin.H{"error": bound.param + " must be an RFC 3339 timestamp"})
+			return
+		}
+		*bound.value = t.Unix()
+	}
+
+	resp, err := h.grpcClient.ListAuditEvents(adminContext(c), req)
+	h.sendAuditEvents(c, resp, err, page, pageSize)
+}
+
+// sendAuditEvents отправляет страницу журнала аудита или ошибку её получения
+func (h *AuthHandler) sendAuditEvents(c *gin.Context, resp *api.ListAuditEventsResponse, err error, page, pageSize int) {
+	if status.Code(err) == codes.InvalidArgument {
+		c.JSON(400, gin.H{"error": "Invalid filter"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to list audit events"})
+		return
+	}
+
+	events := make([]gin.H, 0, len(resp.Events))
+	for _, event := range resp.Events {
+		events = append(events, auditEventJSON(event))
+	}
+
+	c.JSON(200, gin.H{
+		"events":    events,
+		"total":     resp.Total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// auditEventJSON преобразует запись журнала в ответ API (null — актор или цель не заданы,
+// details есть только у действий с параметрами)
+func auditEventJSON(event *api.AuditEventResponse) gin.H {
+	body := gin.H{
+		"id":         event.Id,
+		"action":     event.Action,
+		"actor_id":   nil,
+		"target_id":  nil,
+		"ip":         event.Ip,
+		"user_agent": event.UserAgent,
+		"result":     event.Result,
+		"created_at": time.Unix(event.CreatedAt, 0).UTC(),
+	}
+	if event.ActorId != "" {
+		body["actor_id"] = event.ActorId
+	}
+	if event.TargetId != "" {
+		body["target_id"] = event.TargetId
+	}
+	if len(event.Details) > 0 {
+		body["details"] = event.Details
+	}
+	return body
+}
@@ -2,51 +2,182 @@ package handlers
 
 import (
 	"context"
-	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/oziev02/checklist-microservices/internal/api/domain/entities"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/dataexport"
 	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/grpc_client"
-	"golang.org/x/crypto/bcrypt"
-	"os"
-	"time"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/http/middleware"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/jwtkeys"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/oauth"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/passkey"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/password"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/ratelimit"
+	"github.com/oziev02/checklist-microservices/internal/api/ports"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"strings"
 )
 
 type AuthHandler struct {
-	grpcClient *grpc_client.Client
+	grpcClient     *grpc_client.Client
+	cache          ports.Cache        // Redis или кэш в памяти процесса
+	attempts       ports.AttemptStore // неудачные попытки входа и отправленные письма
+	throttler      *ratelimit.Throttler
+	keys           *jwtkeys.KeyRing // nil: access-токены подписываются HS256 с JWT_SECRET
+	hasher         ports.PasswordHasher
+	dummyHash      string // хэш случайного пароля для входа с неизвестным email
+	passwordPolicy *password.Policy
+	mailer         ports.Mailer
+	oauthProviders map[string]*oauth.Provider
+	passkeys       *passkey.Service // nil, если WebAuthn не настроен
+	exports        *dataexport.Store
 }
 
-func NewAuthHandler(grpcClient *grpc_client.Client) *AuthHandler {
-	return &AuthHandler{grpcClient: grpcClient}
+func NewAuthHandler(grpcClient *grpc_client.Client, cache ports.Cache, attempts ports.AttemptStore, keys *jwtkeys.KeyRing, mailer ports.Mailer, exports *dataexport.Store) *AuthHandler {
+	passkeys, err := passkey.NewFromEnv()
+	if err != nil {
+		log.Printf("Passkeys are disabled: %v", err)
+	}
+
+	// С хэшем случайного пароля сверяется пароль при входе с неизвестным email, чтобы
+	// по времени ответа нельзя было понять, зарегистрирован ли адрес
+	hasher := password.NewHasherFromEnv()
+	dummyHash, err := dummyPasswordHash(hasher)
+	if err != nil {
+		log.Printf("Failed to hash dummy password: %v", err)
+	}
+
+	return &AuthHandler{
+		grpcClient:     grpcClient,
+		cache:          cache,
+		attempts:       attempts,
+		throttler:      ratelimit.NewThrottlerFromEnv(attempts),
+		keys:           keys,
+		hasher:         hasher,
+		dummyHash:      dummyHash,
+		passwordPolicy: password.NewPolicyFromEnv(),
+		mailer:         mailer,
+		oauthProviders: oauth.NewProvidersFromEnv(),
+		passkeys:       passkeys,
+		exports:        exports,
+	}
 }
 
 // Регистрирует все маршруты API
-func RegisterRoutes(r *gin.Engine, grpcClient *grpc_client.Client) {
-	authHandler := NewAuthHandler(grpcClient)
-	taskHandler := NewTaskHandler(grpcClient)
-	profileHandler := NewProfileHandler(grpcClient)
+func RegisterRoutes(r *gin.Engine, grpcClient *grpc_client.Client, cache ports.Cache, attempts ports.AttemptStore, keys *jwtkeys.KeyRing, mailer ports.Mailer, exports *dataexport.Store) {
+	registerRoutes(r, NewAuthHandler(grpcClient, cache, attempts, keys, mailer, exports), NewTaskHandler(grpcClient), NewProfileHandler(grpcClient))
+}
 
+// registerRoutes регистрирует маршруты с уже созданными обработчиками
+func registerRoutes(r *gin.Engine, authHandler *AuthHandler, taskHandler *TaskHandler, profileHandler *ProfileHandler) {
 	// Аутентификация
 	r.POST("/register", authHandler.registerHandler)
 	r.POST("/login", authHandler.loginHandler)
-	r.POST("/2fa/setup", authHandler.setup2FAHandler)
-	r.POST("/2fa/verify", authHandler.verify2FAHandler)
+	r.POST("/login/2fa", authHandler.login2FAHandler)
+	r.POST("/login/2fa/passkey/begin", authHandler.beginPasskeyTwoFAHandler)
+	r.POST("/login/2fa/passkey/finish", authHandler.finishPasskeyTwoFAHandler)
+	r.POST("/login/passkey/begin", authHandler.beginPasskeyLoginHandler)
+	r.POST("/login/passkey/finish", authHandler.finishPasskeyLoginHandler)
+	r.POST("/login/magic", authHandler.magicLinkHandler)
+	r.POST("/login/magic/callback", authHandler.magicLinkCallbackHandler)
+	r.POST("/token/refresh", authHandler.refreshTokenHandler)
+
+	// Открытые ключи для проверки access-токенов другими сервисами
+	r.GET("/.well-known/jwks.json", authHandler.jwksHandler)
+
+	// Восстановление пароля
+	r.POST("/password/forgot", authHandler.forgotPasswordHandler)
+	r.POST("/password/reset", authHandler.resetPasswordHandler)
+
+	// Подтверждение email
+	r.POST("/verify-email", authHandler.verifyEmailHandler)
+	r.POST("/verify-email/resend", authHandler.resendVerificationEmailHandler)
+
+	// Скачивание выгрузки данных по подписанной ссылке из письма
+	r.GET("/account/export/:id/download", authHandler.downloadExportHandler)
 
-	auth := r.Group("/", authHandler.authMiddleware)
+	// Вход через Google и GitHub
+	r.GET("/oauth/:provider/start", authHandler.oauthStartHandler)
+	r.GET("/oauth/:provider/callback", authHandler.oauthCallbackHandler)
+
+	auth := r.Group("/", authHandler.authMiddleware, middleware.LogImpersonation(authHandler.recordImpersonatedRequest))
 	{
+		// Администратор, действующий от имени пользователя, не может менять способы входа,
+		// выдавать долгоживущие доступы, выгружать данные и удалять аккаунт
+		notImpersonated := middleware.DenyImpersonation()
+
+		// Управление аккаунтом недоступно персональным токенам
+		account := auth.Group("/", middleware.RequireSession())
+		{
+			// 2FA
+			account.POST("/2fa/setup", notImpersonated, authHandler.setup2FAHandler)
+			account.POST("/2fa/verify", notImpersonated, authHandler.verify2FAHandler)
+			account.POST("/2fa/recovery-codes", notImpersonated, authHandler.regenerateRecoveryCodesHandler)
+
+			// Смена пароля
+			account.PUT("/password", notImpersonated, authHandler.changePasswordHandler)
+
+			// Удаление аккаунта
+			account.DELETE("/account", notImpersonated, authHandler.deleteAccountHandler)
+
+			// Выгрузка данных
+			account.POST("/account/export", notImpersonated, authHandler.createExportHandler)
+			account.GET("/account/export/:id", notImpersonated, authHandler.getExportHandler)
+
+			// История безопасности
+			account.GET("/account/activity", authHandler.accountActivityHandler)
+
+			// Выход
+			account.POST("/logout", authHandler.logoutHandler)
+			account.POST("/logout/all", notImpersonated, authHandler.logoutAllHandler)
+
+			// Сессии
+			account.GET("/sessions", authHandler.listSessionsHandler)
+			account.DELETE("/sessions/:id", notImpersonated, authHandler.deleteSessionHandler)
+
+			// Персональные токены
+			account.POST("/tokens", notImpersonated, authHandler.createPersonalAccessTokenHandler)
+			account.GET("/tokens", authHandler.listPersonalAccessTokensHandler)
+			account.DELETE("/tokens/:id", notImpersonated, authHandler.deletePersonalAccessTokenHandler)
+
+			// Passkey (WebAuthn)
+			account.POST("/passkeys/register/begin", notImpersonated, authHandler.beginPasskeyRegistrationHandler)
+			account.POST("/passkeys/register/finish", notImpersonated, authHandler.finishPasskeyRegistrationHandler)
+			account.GET("/passkeys", authHandler.listPasskeysHandler)
+			account.DELETE("/passkeys/:id", notImpersonated, authHandler.deletePasskeyHandler)
+		}
+
+		// Администрирование пользователей
+		admin := auth.Group("/admin", middleware.RequireSession(), middleware.RequireRole(roleAdmin))
+		{
+			admin.GET("/users", authHandler.adminListUsersHandler)
+			admin.GET("/users/:id", authHandler.adminGetUserHandler)
+			admin.POST("/users/:id/disable", authHandler.adminDisableUserHandler)
+			admin.POST("/users/:id/enable", authHandler.adminEnableUserHandler)
+			admin.POST("/users/:id/logout", authHandler.adminLogoutUserHandler)
+			admin.POST("/users/:id/impersonate", authHandler.adminImpersonateUserHandler)
+			admin.DELETE("/impersonations/:sid", authHandler.adminRevokeImpersonationHandler)
+			admin.DELETE("/users/:id/2fa", authHandler.adminResetTwoFAHandler)
+			admin.DELETE("/users/:id", authHandler.adminDeleteUserHandler)
+			admin.GET("/audit-events", authHandler.adminListAuditEventsHandler)
+		}
+
 		// Tasks
-		auth.POST("/create", taskHandler.createTaskHandler)
-		auth.GET("/list", taskHandler.listTasksHandler)
-		auth.DELETE("/delete", taskHandler.deleteTaskHandler)
-		auth.PUT("/done", taskHandler.markTaskDoneHandler)
+		auth.POST("/create", middleware.RequireScope(scopeTasksWrite), authHandler.requireVerifiedEmail, taskHandler.createTaskHandler)
+		auth.GET("/list", middleware.RequireScope(scopeTasksRead), taskHandler.listTasksHandler)
+		auth.DELETE("/delete", middleware.RequireScope(scopeTasksWrite), taskHandler.deleteTaskHandler)
+		auth.PUT("/done", middleware.RequireScope(scopeTasksWrite), taskHandler.markTaskDoneHandler)
 
 		// Profile
-		auth.GET("/profile", profileHandler.getProfileHandler)
-		auth.PUT("/profile", profileHandler.updateProfileHandler)
+		auth.GET("/profile", middleware.RequireScope(scopeProfileRead), profileHandler.getProfileHandler)
+		auth.PUT("/profile", middleware.RequireScope(scopeProfileWrite), profileHandler.updateProfileHandler)
 	}
 }
 
-// authMiddleware проверяет JWT-токен
+// authMiddleware проверяет JWT-токен или персональный токен доступа (с префиксом clp_)
 func (h *AuthHandler) authMiddleware(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
@@ -63,12 +194,12 @@ func (h *AuthHandler) authMiddleware(c *gin.Context) {
 	}
 
 	tokenString := authHeader[7:]
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(os.Getenv("JWT_SECRET")), nil
-	})
+	if strings.HasPrefix(tokenString, personalAccessTokenPrefix) {
+		h.authenticatePersonalAccessToken(c, tokenString)
+		return
+	}
+
+	token, err := jwt.Parse(tokenString, h.accessTokenKeyfunc)
 
 	if err != nil || !token.Valid {
 		c.JSON(401, gin.H{"error": "Invalid token"})
@@ -84,6 +215,13 @@ func (h *AuthHandler) authMiddleware(c *gin.Context) {
 		return
 	}
 
+	// Принимаем только access-токены: challenge-токены 2FA подписаны тем же секретом
+	if claims["typ"] != tokenTypeAccess {
+		c.JSON(401, gin.H{"error": "Invalid token type"})
+		c.Abort()
+		return
+	}
+
 	// Сохраняем user_id в контексте для использования в других обработчиках
 	userID, ok := claims["user_id"].(string)
 	if !ok {
@@ -92,34 +230,73 @@ func (h *AuthHandler) authMiddleware(c *gin.Context) {
 		return
 	}
 
+	// Проверяем, не отозван ли токен
+	revoked, err := h.isAccessTokenRevoked(c.Request.Context(), claims)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to check token revocation"})
+		c.Abort()
+		return
+	}
+	if revoked {
+		c.JSON(401, gin.H{"error": "Token has been revoked"})
+		c.Abort()
+		return
+	}
+
+	// Отключение аккаунта действует и на уже выданные токены
+	if !h.requireActiveUser(c, userID) {
+		return
+	}
+
+	sessionID, _ := claims["sid"].(string)
+	actorID := claimActor(claims)
 	c.Set("user_id", userID)
+	c.Set("token_claims", claims)
+	if actorID != "" {
+		// Обработчики видят пользователя в user_id, а администратора — в actor_id
+		c.Set("actor_id", actorID)
+	}
+	c.Set(middleware.PrincipalKey, &middleware.Principal{
+		UserID:    userID,
+		SessionID: sessionID,
+		Roles:     claimRoles(claims),
+		Scopes:    claimScopes(claims),
+		ActorID:   actorID,
+	})
 	c.Next()
 }
 
 func (h *AuthHandler) registerHandler(c *gin.Context) {
 	var user entities.User
-	if err := c.ShouldBindJSON(&user); err != nil {
+	if err := c.ShouldBindJSON(&user); err != nil || user.Email == "" {
 		c.JSON(400, gin.H{"error": "Invalid request payload"})
 		return
 	}
 
+	if !h.checkPasswordPolicy(c, user.Password, user.Email) {
+		return
+	}
+
 	// Хэшируем пароль
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	hashedPassword, err := h.hasher.Hash(user.Password)
 	if err != nil {
 		c.JSON(500, gin.H{"error": "Failed to hash password"})
 		return
 	}
 
 	// Отправляем запрос в БД-сервис через gRPC
-	resp, err := h.grpcClient.CreateUser(context.Background(), user.Email, user.Password)
+	resp, err := h.grpcClient.CreateUser(context.Background(), user.Email, hashedPassword)
 	if err != nil {
 		c.JSON(500, gin.H{"error": "Failed to create user"})
 		return
 	}
 
+	go h.sendVerificationEmail(resp.Id, resp.Email)
+
 	c.JSON(200, gin.H{
-		"id":    resp.Id,
-		"email": resp.Email,
+		"id":             resp.Id,
+		"email":          resp.Email,
+		"email_verified": resp.EmailVerified,
 	})
 }
 
@@ -133,73 +310,128 @@ func (h *AuthHandler) loginHandler(c *gin.Context) {
 		return
 	}
 
+	// Защита от перебора паролей по аккаунту и по IP
+	if h.throttled(c, req.Email) {
+		return
+	}
+
 	// Отправляем запрос в БД-сервис через gRPC
 	userResp, err := h.grpcClient.GetUserByEmail(context.Background(), req.Email)
-	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to get user"})
+	if status.Code(err) == codes.NotFound {
+		h.verifyPassword(req.Password, "")
+		h.recordFailure(c, req.Email)
+		c.JSON(401, gin.H{"error": "Invalid email or password"})
 		return
 	}
-	if userResp == nil || userResp.Password != req.Password {
-		c.JSON(401, gin.H{"error": "Invalid email or password"})
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get user"})
 		return
 	}
 
 	// Проверяем пароль
-	if err := bcrypt.CompareHashAndPassword([]byte(userResp.Password), []byte(req.Password)); err != nil {
-		c.JSON(401, gin.H{"error": "Invalid email or password"})
+	ok, needsRehash, err := h.verifyPassword(req.Password, userResp.Password)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to verify password"})
 		return
 	}
-
-	// Генерируем JWT-токен
-	accessToken, err := generateToken(userResp.Id, false)
-	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to generate access token"})
+	if !ok {
+		h.recordFailure(c, req.Email)
+		recordAuditAs(h.grpcClient, c, "", auditActionLogin, userResp.Id, false)
+		c.JSON(401, gin.H{"error": "Invalid email or password"})
 		return
 	}
+	h.recordSuccess(c, req.Email)
 
-	// Генерируем Refresh-токен
-	refreshToken, err := generateToken(userResp.Id, true)
-	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to generate refresh token"})
-		return
+	// Пароль известен только сейчас: переводим хэш на актуальные алгоритм и параметры
+	if needsRehash {
+		h.rehashPassword(loginContext(userResp.Id), userResp.Id, req.Password, userResp.Password)
 	}
 
-	c.JSON(200, gin.H{
-		"access_token":  "placeholder-access-token",
-		"refresh_token": "placeholder-refresh-token",
-	})
+	h.completeLogin(c, userResp)
 }
 
-// настраивает 2FA для пользователя
-func (h *AuthHandler) setup2FAHandler(c *gin.Context) {
-	c.JSON(200, gin.H{"message": "2FA setup endpoint placeholder"})
+// verifyPassword проверяет пароль. Если пароля нет (пользователь не найден или вошёл через
+// OAuth), пароль сверяется с фиктивным хэшем: проверка занимает столько же времени
+func (h *AuthHandler) verifyPassword(plainPassword, hash string) (bool, bool, error) {
+	if hash == "" {
+		h.hasher.Verify(plainPassword, h.dummyHash)
+		return false, false, nil
+	}
+	return h.hasher.Verify(plainPassword, hash)
 }
 
-// проверяет 2FA-код
-func (h *AuthHandler) verify2FAHandler(c *gin.Context) {
-	c.JSON(200, gin.H{"message": "2FA verify endpoint placeholder"})
+// dummyPasswordHash хэширует случайный пароль с текущими параметрами хэширования
+func dummyPasswordHash(hasher ports.PasswordHasher) (string, error) {
+	plainPassword, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	return hasher.Hash(plainPassword)
 }
 
-func generateToken(userID string, isRefresh bool) (string, error) {
-	var secret string
-	var expiration time.Duration
+// rehashPassword сохраняет новый хэш пароля. Ошибка не мешает входу: хэш пересчитается при следующем
+func (h *AuthHandler) rehashPassword(ctx context.Context, userID, plainPassword, oldHash string) {
+	newHash, err := h.hasher.Hash(plainPassword)
+	if err != nil {
+		log.Printf("Failed to rehash password: %v", err)
+		return
+	}
+	if err := h.grpcClient.RehashPassword(ctx, userID, oldHash, newHash); err != nil {
+		log.Printf("Failed to save rehashed password: %v", err)
+	}
+}
 
-	if isRefresh {
-		secret = os.Getenv("REFRESH_TOKEN_SECRET")
-		expiration = 7 * 24 * time.Hour // 7 дней для Refresh-токена
-	} else {
-		secret = os.Getenv("JWT_SECRET")
-		expiration = 15 * time.Minute // 15 минут для Access-токена
+// completeLogin завершает вход после проверки первого фактора (пароль, OAuth, ссылка из письма).
+// При включённой 2FA или зарегистрированных passkey токены выдаются только после
+// проверки второго фактора в /login/2fa или /login/2fa/passkey
+func (h *AuthHandler) completeLogin(c *gin.Context, userResp *api.UserResponse) {
+	if !loginAllowed(c, userResp) {
+		return
 	}
 
-	if secret == "" {
-		return "", fmt.Errorf("JWT_SECRET or REFRESH_TOKEN_SECRET not set in .env")
+	hasPasskeys, err := h.hasPasskeys(loginContext(userResp.Id), userResp.Id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load passkeys"})
+		return
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userID,
-		"exp": time.Now().Add(expiration).Unix(),
-	})
+	if userResp.TwofaEnabled || hasPasskeys {
+		challengeToken, err := generateChallengeToken(userResp.Id)
+		if err != nil {
+			c.JSON(500, gin.H{"error": "Failed to generate challenge token"})
+			return
+		}
+
+		var methods []string
+		if userResp.TwofaEnabled {
+			methods = append(methods, "totp")
+		}
+		if hasPasskeys {
+			methods = append(methods, "passkey")
+		}
 
-	return token.SignedString([]byte(secret))
+		c.JSON(200, gin.H{
+			"mfa_required":    true,
+			"mfa_methods":     methods,
+			"challenge_token": challengeToken,
+			"expires_in":      int(challengeTokenTTL.Seconds()),
+		})
+		return
+	}
+
+	h.issueTokens(c, userResp)
+}
+
+// loginAllowed проверяет, можно ли выдать пользователю токены, и иначе отвечает ошибкой.
+// Вход в аккаунт со статусом pending_deletion разрешён: он отменяет удаление
+func loginAllowed(c *gin.Context, userResp *api.UserResponse) bool {
+	if userResp.Status != userStatusActive && userResp.Status != userStatusPendingDeletion {
+		c.JSON(403, gin.H{"error": accountStatusError(userResp.Status)})
+		return false
+	}
+	if emailVerificationPolicy() == emailVerificationPolicyLogin && !userResp.EmailVerified {
+		c.JSON(403, gin.H{"error": "Email is not verified"})
+		return false
+	}
+	return true
 }
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/grpc_client"
+)
+
+// userContext — контекст вызова БД-сервиса от имени пользователя, аутентифицированного
+// в запросе (authMiddleware). БД-сервис ограничивает вызов данными этого пользователя,
+// поэтому ID другого пользователя в аргументах вызова им отклоняется
+func userContext(c *gin.Context) context.Context {
+	return grpc_client.AsUser(context.Background(), c.GetString("user_id"))
+}
+
+// adminContext — контекст вызова БД-сервиса от имени администратора запроса
+// над чужим аккаунтом. Роль администратора БД-сервис проверяет сам
+func adminContext(c *gin.Context) context.Context {
+	return grpc_client.AsAdmin(context.Background(), c.GetString("user_id"))
+}
+
+// loginContext — контекст вызова БД-сервиса до аутентификации запроса: пользователь userID
+// определён данными входа (паролем, challenge-токеном, passkey, токеном доступа или из письма)
+// либо найден по email, чтобы отправить ему письмо, а не взят из аргументов вызова
+func loginContext(userID string) context.Context {
+	return grpc_client.AsUser(context.Background(), userID)
+}
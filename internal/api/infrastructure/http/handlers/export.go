@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/dataexport"
+)
+
+const tokenTypeExportDownload = "export_download"
+
+// createExportHandler запускает сборку архива со всеми данными пользователя.
+// Когда архив готов, пользователь получает письмо со ссылкой на скачивание
+func (h *AuthHandler) createExportHandler(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	job, err := h.exports.Begin(userID)
+	if errors.Is(err, dataexport.ErrInProgress) {
+		c.JSON(409, gin.H{"error": "An export is already in progress"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to start export"})
+		return
+	}
+
+	recordOwnAudit(h.grpcClient, c, auditActionDataExport, true)
+
+	go h.buildExport(userContext(c), job)
+
+	c.JSON(202, exportJSON(job.Export, ""))
+}
+
+// removeExports удаляет архивы выгрузок пользователя, чей аккаунт удаляется: после удаления
+// аккаунта его данные не должны оставаться на диске до истечения срока хранения
+func (h *AuthHandler) removeExports(userID string) {
+	if err := h.exports.RemoveUser(userID); err != nil {
+		log.Printf("Failed to remove exports of user %s: %v", userID, err)
+	}
+}
+
+// getExportHandler возвращает статус выгрузки и ссылку на скачивание готового архива
+func (h *AuthHandler) getExportHandler(c *gin.Context) {
+	export, err := h.exports.Get(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get export"})
+		return
+	}
+	if export == nil {
+		c.JSON(404, gin.H{"error": "Export not found"})
+		return
+	}
+
+	downloadURL := ""
+	if export.Status == dataexport.StatusReady {
+		token, err := generateExportDownloadToken(export)
+		if err != nil {
+			c.JSON(500, gin.H{"error": "Failed to generate download link"})
+			return
+		}
+		downloadURL = exportDownloadPath(export) + "?token=" + token
+	}
+
+	c.JSON(200, exportJSON(export, downloadURL))
+}
+
+// downloadExportHandler отдаёт архив по подписанной ссылке. Ссылка не требует заголовка
+// Authorization, чтобы её можно было открыть из письма, и действует, пока архив хранится
+func (h *AuthHandler) downloadExportHandler(c *gin.Context) {
+	claims, err := parseToken(c.Query("token"), os.Getenv("JWT_SECRET"), tokenTypeExportDownload)
+	if err != nil || claims["export_id"] != c.Param("id") {
+		c.JSON(401, gin.H{"error": "Invalid or expired download link"})
+		return
+	}
+	userID, _ := claims["user_id"].(string)
+
+	export, err := h.exports.Get(userID, c.Param("id"))
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get export"})
+		return
+	}
+	if export == nil || export.Status != dataexport.StatusReady {
+		c.JSON(404, gin.H{"error": "Export not found"})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.FileAttachment(h.exports.Path(export), "checklist-export-"+export.CreatedAt.UTC().Format("2006-01-02")+".zip")
+}
+
+// buildExport собирает архив из потока БД-сервиса и сообщает пользователю о готовности.
+// ctx — контекст вызовов БД-сервиса от имени пользователя, запросившего выгрузку
+func (h *AuthHandler) buildExport(ctx context.Context, job *dataexport.Job) {
+	export := job.Export
+	if err := job.Finish(h.writeExportArchive(ctx, export.UserID, job)); err != nil {
+		log.Printf("Failed to build export %s: %v", export.ID, err)
+		return
+	}
+
+	userResp, err := h.grpcClient.GetUserByID(ctx, export.UserID)
+	if err != nil {
+		log.Printf("Failed to get user for export email: %v", err)
+		return
+	}
+	token, err := generateExportDownloadToken(export)
+	if err != nil {
+		log.Printf("Failed to generate export download token: %v", err)
+		return
+	}
+
+	link := appURL(exportDownloadPath(export), token)
+	body := "Your data export is ready. Download it using the link below until " +
+		export.ExpiresAt.UTC().Format(time.RFC1123) + ".\n\n" + link +
+		"\n\nIf you did not request an export, change your password."
+	if err := h.mailer.Send(context.Background(), userResp.Email, "Your data export is ready", body); err != nil {
+		log.Printf("Failed to send export email: %v", err)
+	}
+}
+
+// writeExportArchive пишет в w ZIP-архив с файлами, полученными от БД-сервиса
+func (h *AuthHandler) writeExportArchive(ctx context.Context, userID string, w io.Writer) error {
+	stream, err := h.grpcClient.ExportUserData(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+	var file io.Writer
+	currentName := ""
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		// Части одного файла приходят подряд
+		if chunk.File != currentName {
+			file, err = archive.CreateHeader(&zip.FileHeader{
+				Name:     chunk.File,
+				Method:   zip.Deflate,
+				Modified: time.Now(),
+			})
+			if err != nil {
+				return err
+			}
+			currentName = chunk.File
+		}
+		if _, err := file.Write(chunk.Data); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// generateExportDownloadToken подписывает ссылку на архив; она истекает вместе с архивом
+func generateExportDownloadToken(export *dataexport.Export) (string, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return "", fmt.Errorf("JWT_SECRET not set in .env")
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id":   export.UserID,
+		"export_id": export.ID,
+		"typ":       tokenTypeExportDownload,
+		"exp":       export.ExpiresAt.Unix(),
+	})
+
+	return token.SignedString([]byte(secret))
+}
+
+func exportDownloadPath(export *dataexport.Export) string {
+	return "/account/export/" + export.ID + "/download"
+}
+
+// exportJSON преобразует выгрузку в ответ API
+func exportJSON(export *dataexport.Export, downloadURL string) gin.H {
+	body := gin.H{
+		"id":           export.ID,
+		"status":       export.Status,
+		"created_at":   export.CreatedAt.UTC(),
+		"expires_at":   nil,
+		"download_url": nil,
+	}
+	if !export.ExpiresAt.IsZero() {
+		body["expires_at"] = export.ExpiresAt.UTC()
+	}
+	if downloadURL != "" {
+		body["download_url"] = downloadURL
+	}
+	return body
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/cache"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/dataexport"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/grpc_client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// auditService — БД-сервис, который только принимает записи журнала аудита
+type auditService struct {
+	api.UnimplementedChecklistServiceServer
+
+	mu     sync.Mutex
+	events []*api.AuditEventRequest
+}
+
+func (s *auditService) RecordAuditEvent(ctx context.Context, req *api.AuditEventRequest) (*api.Empty, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, req)
+	return &api.Empty{}, nil
+}
+
+// newTestClient подключает gRPC-клиент к service, запущенному в памяти
+func newTestClient(t *testing.T, service api.ChecklistServiceServer) *grpc_client.Client {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	api.RegisterChecklistServiceServer(server, service)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := grpc_client.NewClientFromConn(conn)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// Администратор, действующий от имени пользователя, не может ни запустить выгрузку,
+// ни получить ссылку на уже готовый архив
+func TestExportDeniedWhileImpersonating(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+	ctx := context.Background()
+
+	exports, err := dataexport.NewStore(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit := &auditService{}
+	h := &AuthHandler{cache: cache.NewMemoryCache(), grpcClient: newTestClient(t, audit), exports: exports}
+	r := gin.New()
+	registerRoutes(r, h, NewTaskHandler(h.grpcClient), NewProfileHandler(h.grpcClient))
+
+	userID := uuid.New().String()
+	if err := h.cache.Set(ctx, userStatusKeyPrefix+userID, userStatusActive, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	job, err := exports.Begin(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Finish(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	impersonationToken, err := h.generateImpersonationToken(userID, roleUser, uuid.New().String(), uuid.New().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionToken, err := h.generateAccessToken(userID, roleUser, uuid.New().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		wantStatus int
+	}{
+		{"export status while impersonating", http.MethodGet, "/account/export/" + job.Export.ID, impersonationToken, http.StatusForbidden},
+		{"new export while impersonating", http.MethodPost, "/account/export", impersonationToken, http.StatusForbidden},
+		{"export status of the user", http.MethodGet, "/account/export/" + job.Export.ID, sessionToken, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body: %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+
+	// Отклонённые запросы администратора тоже попадают в журнал аудита
+	audit.mu.Lock()
+	defer audit.mu.Unlock()
+	denied := 0
+	for _, event := range audit.events {
+		if event.Action == auditActionImpersonatedRequest && event.Details["status"] == "403" {
+			denied++
+		}
+	}
+	if denied != 2 {
+		t.Errorf("audited denied impersonated requests = %d, want 2", denied)
+	}
+}
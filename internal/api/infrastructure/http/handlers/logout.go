@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Ключи кэша для отзыва access-токенов
+const (
+	revokedTokenKeyPrefix     = "revoked_token:"      // + jti: отозванный токен
+	revokedSessionKeyPrefix   = "revoked_session:"    // + sid: все токены сессии отозваны
+	tokensValidAfterKeyPrefix = "tokens_valid_after:" // + user_id: токены, выпущенные не позже этого времени (мс), отозваны
+)
+
+// logoutHandler завершает текущую сессию: отзывает access-токен и семейство refresh-токенов
+func (h *AuthHandler) logoutHandler(c *gin.Context) {
+	userID := c.GetString("user_id")
+	claims := c.MustGet("token_claims").(jwt.MapClaims)
+
+	if sid, ok := claims["sid"].(string); ok && sid != "" {
+		// Сессия могла быть уже завершена с другого устройства
+		if _, err := h.revokeSession(userContext(c), userID, sid); err != nil {
+			c.JSON(500, gin.H{"error": "Failed to revoke session"})
+			return
+		}
+	}
+
+	if err := h.revokeAccessToken(c.Request.Context(), claims); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to revoke access token"})
+		return
+	}
+	recordOwnAudit(h.grpcClient, c, auditActionLogout, true)
+
+	c.Status(204)
+}
+
+// logoutAllHandler завершает все сессии пользователя на всех устройствах
+func (h *AuthHandler) logoutAllHandler(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := h.revokeAllSessions(userContext(c), userID); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+	recordOwnAudit(h.grpcClient, c, auditActionLogoutAll, true)
+
+	c.Status(204)
+}
+
+// revokeAllSessions отзывает все refresh-токены пользователя и все выпущенные ему access-токены.
+// ctx — контекст вызова БД-сервиса: от имени самого пользователя или администратора
+func (h *AuthHandler) revokeAllSessions(ctx context.Context, userID string) error {
+	if err := h.grpcClient.RevokeUserRefreshFamilies(ctx, userID); err != nil {
+		return err
+	}
+	return h.revokeUserAccessTokens(ctx, userID)
+}
+
+// revokeUserAccessTokens делает недействительными все уже выпущенные пользователю access-токены
+func (h *AuthHandler) revokeUserAccessTokens(ctx context.Context, userID string) error {
+	// Отметка в миллисекундах: токен, выданный сразу после выхода или сброса пароля
+	// в ту же секунду, должен остаться действительным.
+	// Access-токены живут не дольше accessTokenTTL, дольше хранить отметку не нужно
+	return h.cache.Set(ctx, tokensValidAfterKeyPrefix+userID, time.Now().UnixMilli(), accessTokenTTL)
+}
+
+// issuedAt — значение claim iat с точностью до миллисекунд. RFC 7519 допускает дробный
+// NumericDate, по нему токен сравнивается с отметкой tokens_valid_after:
+func issuedAt(t time.Time) float64 {
+	return float64(t.UnixMilli()) / 1000
+}
+
+// issuedAtMillis возвращает iat токена в миллисекундах. claims.GetIssuedAt не подходит:
+// jwt отбрасывает дробную часть секунды
+func issuedAtMillis(claims jwt.MapClaims) (int64, bool) {
+	iat, ok := claims["iat"].(float64)
+	if !ok {
+		return 0, false
+	}
+	return int64(math.Round(iat * 1000)), true
+}
+
+// revokeAccessToken заносит jti токена в denylist до истечения срока его действия
+func (h *AuthHandler) revokeAccessToken(ctx context.Context, claims jwt.MapClaims) error {
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return nil
+	}
+
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return nil
+	}
+	ttl := time.Until(exp.Time)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return h.cache.Set(ctx, revokedTokenKeyPrefix+jti, 1, ttl)
+}
+
+// isAccessTokenRevoked проверяет denylist по jti и отметку "выход со всех устройств"
+func (h *AuthHandler) isAccessTokenRevoked(ctx context.Context, claims jwt.MapClaims) (bool, error) {
+	if jti, ok := claims["jti"].(string); ok && jti != "" {
+		value, err := h.cache.Get(ctx, revokedTokenKeyPrefix+jti)
+		if err != nil {
+			return false, err
+		}
+		if value != "" {
+			return true, nil
+		}
+	}
+
+	if sid, ok := claims["sid"].(string); ok && sid != "" {
+		value, err := h.cache.Get(ctx, revokedSessionKeyPrefix+sid)
+		if err != nil {
+			return false, err
+		}
+		if value != "" {
+			return true, nil
+		}
+	}
+
+	userID, _ := claims["user_id"].(string)
+	value, err := h.cache.Get(ctx, tokensValidAfterKeyPrefix+userID)
+	if err != nil {
+		return false, err
+	}
+	if value == "" {
+		return false, nil
+	}
+	validAfter, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	iat, ok := issuedAtMillis(claims)
+	if !ok {
+		return true, nil
+	}
+	return iat <= validAfter, nil
+}
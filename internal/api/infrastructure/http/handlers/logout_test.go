@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/cache"
+)
+
+// Токен, выданный после выхода со всех устройств в ту же секунду (например, при входе
+// сразу после сброса пароля), действителен; выданный до выхода — отозван
+func TestRevokeUserAccessTokensSameSecond(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	h := &AuthHandler{cache: cache.NewMemoryCache()}
+	ctx := context.Background()
+
+	// Весь сценарий должен уложиться в одну секунду
+	if next := time.Now().Truncate(time.Second).Add(time.Second); time.Until(next) < 100*time.Millisecond {
+		time.Sleep(time.Until(next))
+	}
+	start := time.Now()
+
+	before := accessTokenClaims(t, h, "user-1")
+	time.Sleep(2 * time.Millisecond)
+	if err := h.revokeUserAccessTokens(ctx, "user-1"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	after := accessTokenClaims(t, h, "user-1")
+
+	if time.Now().Unix() != start.Unix() {
+		t.Fatal("test did not fit into one second")
+	}
+	if revoked, err := h.isAccessTokenRevoked(ctx, before); err != nil || !revoked {
+		t.Errorf("token issued before revocation: revoked = %v, err = %v", revoked, err)
+	}
+	if revoked, err := h.isAccessTokenRevoked(ctx, after); err != nil || revoked {
+		t.Errorf("token issued after revocation in the same second: revoked = %v, err = %v", revoked, err)
+	}
+}
+
+// accessTokenClaims выпускает access-токен userID и возвращает его проверенные claims
+func accessTokenClaims(t *testing.T, h *AuthHandler, userID string) jwt.MapClaims {
+	t.Helper()
+
+	tokenString, err := h.generateAccessToken(userID, roleUser, "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Parse(tokenString, h.accessTokenKeyfunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token.Claims.(jwt.MapClaims)
+}
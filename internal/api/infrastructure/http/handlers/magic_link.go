@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Назначение токена входа по ссылке в email_tokens
+const emailTokenPurposeMagicLink = "magic_link"
+
+// Время жизни ссылки для входа
+const magicLinkTokenTTL = 10 * time.Minute
+
+const magicLinkResendKeyPrefix = "magic_link_resend:"
+
+// magicLinkHandler отправляет одноразовую ссылку для входа без пароля. Как и при сбросе
+// пароля, ответ не зависит от того, зарегистрирован ли email
+func (h *AuthHandler) magicLinkHandler(c *gin.Context) {
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Email == "" {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	if h.emailSentRecently(c, magicLinkResendKeyPrefix, req.Email, "Login link was sent recently, try again later") {
+		return
+	}
+
+	go h.sendMagicLinkEmail(req.Email)
+
+	c.JSON(202, gin.H{"message": "If the email is registered, a login link has been sent"})
+}
+
+// magicLinkCallbackHandler гасит токен из ссылки и завершает вход так же, как после
+// проверки пароля: при включённой 2FA выдаётся challenge-токен вместо пары токенов
+func (h *AuthHandler) magicLinkCallbackHandler(c *gin.Context) {
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Token == "" {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	userResp, err := h.grpcClient.ConsumeMagicLink(context.Background(), hashToken(req.Token))
+	if status.Code(err) == codes.NotFound {
+		c.JSON(401, gin.H{"error": "Invalid or expired login link"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to check login link"})
+		return
+	}
+
+	h.completeLogin(c, userResp)
+}
+
+// sendMagicLinkEmail создаёт токен входа и отправляет ссылку, если пользователь существует
+func (h *AuthHandler) sendMagicLinkEmail(email string) {
+	ctx := context.Background()
+
+	userResp, err := h.grpcClient.GetUserByEmail(ctx, email)
+	if status.Code(err) == codes.NotFound {
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get user for magic link: %v", err)
+		return
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		log.Printf("Failed to generate magic link token: %v", err)
+		return
+	}
+	expiresAt := time.Now().Add(magicLinkTokenTTL)
+	if err := h.grpcClient.CreateEmailToken(loginContext(userResp.Id), userResp.Id, emailTokenPurposeMagicLink, hashToken(token), expiresAt); err != nil {
+		log.Printf("Failed to save magic link token: %v", err)
+		return
+	}
+
+	link := frontendURL("/login/magic", token)
+	body := "To log in, open the link below. It expires in 10 minutes and can be used once.\n\n" +
+		link + "\n\nIf you did not try to log in, ignore this email."
+	if err := h.mailer.Send(ctx, userResp.Email, "Your login link", body); err != nil {
+		log.Printf("Failed to send magic link email: %v", err)
+	}
+}
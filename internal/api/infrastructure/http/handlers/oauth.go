@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Cookie с state и PKCE-verifier живёт, пока пользователь на странице провайдера
+const oauthCookieMaxAge = 10 * 60
+
+// oauthStartHandler перенаправляет пользователя на страницу входа провайдера.
+// state и PKCE-verifier сохраняются в HttpOnly cookie и проверяются в callback
+func (h *AuthHandler) oauthStartHandler(c *gin.Context) {
+	provider, ok := h.oauthProviders[c.Param("provider")]
+	if !ok {
+		c.JSON(404, gin.H{"error": "Unknown OAuth provider"})
+		return
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to generate OAuth state"})
+		return
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	cookiePath := "/oauth/" + provider.Name
+	secure := c.Request.TLS != nil
+	// Lax: cookie должна прийти при редиректе обратно от провайдера
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie("oauth_state", state, oauthCookieMaxAge, cookiePath, "", secure, true)
+	c.SetCookie("oauth_verifier", verifier, oauthCookieMaxAge, cookiePath, "", secure, true)
+
+	c.Redirect(302, provider.AuthCodeURL(state, verifier))
+}
+
+// oauthCallbackHandler принимает код авторизации от провайдера, привязывает учётную запись
+// провайдера к пользователю и выдаёт обычную пару токенов (или challenge, если включена 2FA)
+func (h *AuthHandler) oauthCallbackHandler(c *gin.Context) {
+	provider, ok := h.oauthProviders[c.Param("provider")]
+	if !ok {
+		c.JSON(404, gin.H{"error": "Unknown OAuth provider"})
+		return
+	}
+
+	if errCode := c.Query("error"); errCode != "" {
+		c.JSON(400, gin.H{"error": "OAuth authorization failed: " + errCode})
+		return
+	}
+
+	state, _ := c.Cookie("oauth_state")
+	verifier, _ := c.Cookie("oauth_verifier")
+
+	// Cookie одноразовые
+	cookiePath := "/oauth/" + provider.Name
+	c.SetCookie("oauth_state", "", -1, cookiePath, "", c.Request.TLS != nil, true)
+	c.SetCookie("oauth_verifier", "", -1, cookiePath, "", c.Request.TLS != nil, true)
+
+	if state == "" || verifier == "" || subtle.ConstantTimeCompare([]byte(state), []byte(c.Query("state"))) != 1 {
+		c.JSON(400, gin.H{"error": "Invalid OAuth state"})
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		c.JSON(400, gin.H{"error": "Authorization code is required"})
+		return
+	}
+
+	info, err := provider.Exchange(c.Request.Context(), code, verifier)
+	if err != nil {
+		c.JSON(502, gin.H{"error": "Failed to complete OAuth sign-in"})
+		return
+	}
+	if info.Email == "" {
+		c.JSON(400, gin.H{"error": "OAuth provider did not return an email"})
+		return
+	}
+
+	userResp, err := h.grpcClient.SignInWithIdentity(context.Background(), provider.Name, info.Subject, info.Email, info.EmailVerified)
+	if status.Code(err) == codes.AlreadyExists {
+		c.JSON(409, gin.H{"error": "An account with this email already exists, verify its email before signing in with this provider"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to sign in"})
+		return
+	}
+
+	h.completeLogin(c, userResp)
+}
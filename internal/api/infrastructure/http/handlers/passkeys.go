@@ -0,0 +1,481 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/passkey"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Назначение церемонии WebAuthn (claim "purpose" в токене сессии)
+const (
+	webAuthnPurposeRegister = "register"
+	webAuthnPurposeLogin    = "login" // вход без пароля
+	webAuthnPurposeTwoFA    = "2fa"   // passkey как второй фактор после пароля
+)
+
+const (
+	webAuthnSessionTTL = 5 * time.Minute
+	// Использованные challenge помнятся до истечения токена сессии, чтобы ответ нельзя было повторить
+	usedWebAuthnChallengeKeyPrefix = "webauthn_challenge:"
+	maxPasskeyNameLength           = 100
+)
+
+// beginPasskeyRegistrationHandler возвращает параметры для navigator.credentials.create()
+// и токен сессии, который нужно передать в /passkeys/register/finish
+func (h *AuthHandler) beginPasskeyRegistrationHandler(c *gin.Context) {
+	if !h.passkeysConfigured(c) {
+		return
+	}
+
+	userResp, err := h.grpcClient.GetUserByID(userContext(c), c.GetString("user_id"))
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get user"})
+		return
+	}
+	user, err := h.passkeyUser(userContext(c), userResp)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load passkeys"})
+		return
+	}
+
+	creation, session, err := h.passkeys.BeginRegistration(user)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to start passkey registration"})
+		return
+	}
+	h.sendWebAuthnOptions(c, creation, session, webAuthnPurposeRegister, userResp.Id)
+}
+
+// finishPasskeyRegistrationHandler проверяет ответ аутентификатора и сохраняет passkey.
+// Требует текущий пароль и TOTP-код, если включена 2FA: passkey — способ входа, и украденного
+// access-токена недостаточно, чтобы его добавить
+func (h *AuthHandler) finishPasskeyRegistrationHandler(c *gin.Context) {
+	if !h.passkeysConfigured(c) {
+		return
+	}
+
+	var req struct {
+		SessionToken    string          `json:"session_token"`
+		Name            string          `json:"name"`
+		Credential      json.RawMessage `json:"credential"`
+		CurrentPassword string          `json:"current_password"`
+		Code            string          `json:"code"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.SessionToken == "" || len(req.Credential) == 0 || req.CurrentPassword == "" {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		req.Name = "Passkey"
+	}
+	if len(req.Name) > maxPasskeyNameLength {
+		c.JSON(400, gin.H{"error": "Name must be at most 100 characters"})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	userResp, err := h.grpcClient.GetUserByID(userContext(c), userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	// Повторная аутентификация до использования токена сессии: при неверном пароле
+	// регистрацию можно повторить с тем же ответом аутентификатора
+	if !h.reauthenticate(c, userResp, req.CurrentPassword, req.Code) {
+		return
+	}
+
+	session, err := h.useWebAuthnSession(c.Request.Context(), req.SessionToken, webAuthnPurposeRegister, userID)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid or expired session token"})
+		return
+	}
+
+	user, err := h.passkeyUser(userContext(c), userResp)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load passkeys"})
+		return
+	}
+
+	credential, err := h.passkeys.FinishRegistration(user, *session, req.Credential)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "Invalid passkey registration"})
+		return
+	}
+
+	data, err := passkey.MarshalCredential(credential)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to save passkey"})
+		return
+	}
+	resp, err := h.grpcClient.CreateWebAuthnCredential(userContext(c), userID, passkey.CredentialID(credential.ID), req.Name, data)
+	if status.Code(err) == codes.AlreadyExists {
+		c.JSON(409, gin.H{"error": "Passkey is already registered"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to save passkey"})
+		return
+	}
+	recordOwnAudit(h.grpcClient, c, auditActionPasskeyAdd, true)
+
+	c.JSON(201, passkeyJSON(resp))
+}
+
+// listPasskeysHandler возвращает passkey пользователя
+func (h *AuthHandler) listPasskeysHandler(c *gin.Context) {
+	resp, err := h.grpcClient.ListWebAuthnCredentials(userContext(c), c.GetString("user_id"))
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to list passkeys"})
+		return
+	}
+
+	passkeys := make([]gin.H, 0, len(resp.Credentials))
+	for _, credential := range resp.Credentials {
+		passkeys = append(passkeys, passkeyJSON(credential))
+	}
+
+	c.JSON(200, passkeys)
+}
+
+// deletePasskeyHandler удаляет passkey пользователя
+func (h *AuthHandler) deletePasskeyHandler(c *gin.Context) {
+	credentialID := c.Param("id")
+	if _, err := passkey.ParseCredentialID(credentialID); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid passkey id"})
+		return
+	}
+
+	err := h.grpcClient.DeleteWebAuthnCredential(userContext(c), c.GetString("user_id"), credentialID)
+	if status.Code(err) == codes.NotFound {
+		c.JSON(404, gin.H{"error": "Passkey not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to delete passkey"})
+		return
+	}
+	recordOwnAudit(h.grpcClient, c, auditActionPasskeyRemove, true)
+
+	c.Status(204)
+}
+
+// beginPasskeyLoginHandler начинает вход без пароля: пользователь выбирает passkey в браузере
+func (h *AuthHandler) beginPasskeyLoginHandler(c *gin.Context) {
+	if !h.passkeysConfigured(c) {
+		return
+	}
+
+	assertion, session, err := h.passkeys.BeginDiscoverableLogin()
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to start passkey login"})
+		return
+	}
+	h.sendWebAuthnOptions(c, assertion, session, webAuthnPurposeLogin, "")
+}
+
+// finishPasskeyLoginHandler проверяет подпись passkey и выдаёт пару токенов.
+// Passkey с проверкой пользователя (PIN, биометрия) уже двухфакторный, поэтому 2FA не запрашивается
+func (h *AuthHandler) finishPasskeyLoginHandler(c *gin.Context) {
+	if !h.passkeysConfigured(c) {
+		return
+	}
+
+	var req struct {
+		SessionToken string          `json:"session_token"`
+		Credential   json.RawMessage `json:"credential"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.SessionToken == "" || len(req.Credential) == 0 {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	session, err := h.useWebAuthnSession(c.Request.Context(), req.SessionToken, webAuthnPurposeLogin, "")
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Invalid or expired session token"})
+		return
+	}
+
+	var userResp *api.UserResponse
+	user, credential, err := h.passkeys.FinishDiscoverableLogin(*session, req.Credential, func(credentialID, userHandle []byte) (*passkey.User, error) {
+		stored, err := h.grpcClient.GetWebAuthnCredential(context.Background(), passkey.CredentialID(credentialID))
+		if err != nil {
+			return nil, err
+		}
+		// Владелец passkey нужен для проверки подписи
+		userResp, err = h.grpcClient.GetUserByID(loginContext(stored.UserId), stored.UserId)
+		if err != nil {
+			return nil, err
+		}
+		return h.passkeyUser(loginContext(userResp.Id), userResp)
+	})
+	if err != nil || credential.Authenticator.CloneWarning {
+		c.JSON(401, gin.H{"error": "Invalid passkey"})
+		return
+	}
+	h.savePasskeyUsage(loginContext(userResp.Id), user, credential)
+
+	if !loginAllowed(c, userResp) {
+		return
+	}
+	h.issueTokens(c, userResp)
+}
+
+// beginPasskeyTwoFAHandler начинает проверку passkey как второго фактора после пароля
+func (h *AuthHandler) beginPasskeyTwoFAHandler(c *gin.Context) {
+	if !h.passkeysConfigured(c) {
+		return
+	}
+
+	var req struct {
+		ChallengeToken string `json:"challenge_token"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.ChallengeToken == "" {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	userID, _, err := parseChallengeToken(req.ChallengeToken)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Invalid or expired challenge token"})
+		return
+	}
+
+	userResp, err := h.grpcClient.GetUserByID(loginContext(userID), userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get user"})
+		return
+	}
+	user, err := h.passkeyUser(loginContext(userID), userResp)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load passkeys"})
+		return
+	}
+	if len(user.Credentials) == 0 {
+		c.JSON(400, gin.H{"error": "No passkeys registered"})
+		return
+	}
+
+	assertion, session, err := h.passkeys.BeginLogin(user)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to start passkey login"})
+		return
+	}
+	h.sendWebAuthnOptions(c, assertion, session, webAuthnPurposeTwoFA, userResp.Id)
+}
+
+// finishPasskeyTwoFAHandler обменивает challenge-токен и подпись passkey на пару токенов
+func (h *AuthHandler) finishPasskeyTwoFAHandler(c *gin.Context) {
+	if !h.passkeysConfigured(c) {
+		return
+	}
+
+	var req struct {
+		ChallengeToken string          `json:"challenge_token"`
+		SessionToken   string          `json:"session_token"`
+		Credential     json.RawMessage `json:"credential"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.ChallengeToken == "" || req.SessionToken == "" || len(req.Credential) == 0 {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	userID, challengeID, err := parseChallengeToken(req.ChallengeToken)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Invalid or expired challenge token"})
+		return
+	}
+	// Как и в login2FAHandler, токен гасится до проверки passkey
+	if !h.consumeChallengeToken(c, challengeID) {
+		return
+	}
+
+	if h.throttled(c, throttleTwoFAPrefix+userID) {
+		return
+	}
+
+	session, err := h.useWebAuthnSession(c.Request.Context(), req.SessionToken, webAuthnPurposeTwoFA, userID)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Invalid or expired session token"})
+		return
+	}
+
+	userResp, err := h.grpcClient.GetUserByID(loginContext(userID), userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get user"})
+		return
+	}
+	user, err := h.passkeyUser(loginContext(userID), userResp)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load passkeys"})
+		return
+	}
+
+	credential, err := h.passkeys.FinishLogin(user, *session, req.Credential)
+	if err != nil || credential.Authenticator.CloneWarning {
+		h.recordFailure(c, throttleTwoFAPrefix+userID)
+		recordAuditAs(h.grpcClient, c, "", auditActionLogin, userID, false)
+		c.JSON(401, gin.H{"error": "Invalid passkey"})
+		return
+	}
+	h.recordSuccess(c, throttleTwoFAPrefix+userID)
+	h.savePasskeyUsage(loginContext(userResp.Id), user, credential)
+
+	// Аккаунт могли отключить между вводом пароля и второго фактора
+	if !loginAllowed(c, userResp) {
+		return
+	}
+	h.issueTokens(c, userResp)
+}
+
+// passkeysConfigured отвечает 404, если Relying Party не настроен
+func (h *AuthHandler) passkeysConfigured(c *gin.Context) bool {
+	if h.passkeys == nil {
+		c.JSON(404, gin.H{"error": "Passkeys are not configured"})
+		return false
+	}
+	return true
+}
+
+// passkeyUser загружает passkey пользователя из БД-сервиса
+func (h *AuthHandler) passkeyUser(ctx context.Context, userResp *api.UserResponse) (*passkey.User, error) {
+	userID, err := uuid.Parse(userResp.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.grpcClient.ListWebAuthnCredentials(ctx, userResp.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &passkey.User{ID: userID, Email: userResp.Email}
+	for _, stored := range resp.Credentials {
+		credential, err := passkey.UnmarshalCredential(stored.Data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode passkey %s: %v", stored.CredentialId, err)
+		}
+		user.Credentials = append(user.Credentials, credential)
+	}
+	return user, nil
+}
+
+// hasPasskeys сообщает, может ли пользователь подтвердить вход passkey
+func (h *AuthHandler) hasPasskeys(ctx context.Context, userID string) (bool, error) {
+	if h.passkeys == nil {
+		return false, nil
+	}
+	resp, err := h.grpcClient.ListWebAuthnCredentials(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return len(resp.Credentials) > 0, nil
+}
+
+// savePasskeyUsage сохраняет новый счётчик подписей; ошибка не мешает входу
+func (h *AuthHandler) savePasskeyUsage(ctx context.Context, user *passkey.User, credential *webauthn.Credential) {
+	data, err := passkey.MarshalCredential(credential)
+	if err == nil {
+		err = h.grpcClient.UpdateWebAuthnCredentialUsage(ctx, user.ID.String(), passkey.CredentialID(credential.ID), data)
+	}
+	if err != nil {
+		log.Printf("Failed to update passkey of user %s: %v", user.ID, err)
+	}
+}
+
+// sendWebAuthnOptions отправляет параметры для navigator.credentials.create()/get()
+// вместе с токеном сессии церемонии
+func (h *AuthHandler) sendWebAuthnOptions(c *gin.Context, options interface{}, session *webauthn.SessionData, purpose, userID string) {
+	sessionToken, err := generateWebAuthnSessionToken(session, purpose, userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to generate session token"})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"options":       options,
+		"session_token": sessionToken,
+		"expires_in":    int(webAuthnSessionTTL.Seconds()),
+	})
+}
+
+// generateWebAuthnSessionToken подписывает состояние церемонии, чтобы не хранить его на сервере
+func generateWebAuthnSessionToken(session *webauthn.SessionData, purpose, userID string) (string, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return "", fmt.Errorf("JWT_SECRET not set in .env")
+	}
+
+	sessionJSON, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"purpose": purpose,
+		"session": string(sessionJSON),
+		"typ":     tokenTypeWebAuthnSession,
+		"exp":     time.Now().Add(webAuthnSessionTTL).Unix(),
+	})
+
+	return token.SignedString([]byte(secret))
+}
+
+// useWebAuthnSession проверяет токен сессии церемонии и отмечает её challenge использованным,
+// чтобы один ответ аутентификатора нельзя было принять дважды
+func (h *AuthHandler) useWebAuthnSession(ctx context.Context, tokenString, purpose, userID string) (*webauthn.SessionData, error) {
+	claims, err := parseToken(tokenString, os.Getenv("JWT_SECRET"), tokenTypeWebAuthnSession)
+	if err != nil {
+		return nil, err
+	}
+	if claims["purpose"] != purpose || claims["user_id"] != userID {
+		return nil, fmt.Errorf("Session token was issued for another ceremony")
+	}
+
+	sessionJSON, _ := claims["session"].(string)
+	var session webauthn.SessionData
+	if err := json.Unmarshal([]byte(sessionJSON), &session); err != nil {
+		return nil, fmt.Errorf("Invalid session in token: %v", err)
+	}
+
+	fresh, err := h.cache.SetIfAbsent(ctx, usedWebAuthnChallengeKeyPrefix+session.Challenge, 1, webAuthnSessionTTL)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		return nil, fmt.Errorf("Session token has already been used")
+	}
+
+	return &session, nil
+}
+
+// passkeyJSON преобразует passkey в ответ API (без ключа и счётчика подписей)
+func passkeyJSON(credential *api.WebAuthnCredentialResponse) gin.H {
+	body := gin.H{
+		"id":           credential.CredentialId,
+		"name":         credential.Name,
+		"created_at":   time.Unix(credential.CreatedAt, 0).UTC(),
+		"last_used_at": nil,
+	}
+	if credential.LastUsedAt != 0 {
+		body["last_used_at"] = time.Unix(credential.LastUsedAt, 0).UTC()
+	}
+	return body
+}
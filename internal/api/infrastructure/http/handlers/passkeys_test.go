@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/cache"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/passkey"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/passkey/softauthn"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/password"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/ratelimit"
+)
+
+func TestWebAuthnSessionIsSingleUse(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	h := &AuthHandler{cache: cache.NewMemoryCache()}
+	ctx := context.Background()
+
+	session := &webauthn.SessionData{Challenge: "challenge-1", UserID: []byte("user-1")}
+	token, err := generateWebAuthnSessionToken(session, webAuthnPurposeTwoFA, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.useWebAuthnSession(ctx, token, webAuthnPurposeLogin, "user-1"); err == nil {
+		t.Error("session accepted for another purpose")
+	}
+	if _, err := h.useWebAuthnSession(ctx, token, webAuthnPurposeTwoFA, "user-2"); err == nil {
+		t.Error("session accepted for another user")
+	}
+
+	used, err := h.useWebAuthnSession(ctx, token, webAuthnPurposeTwoFA, "user-1")
+	if err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if used.Challenge != session.Challenge {
+		t.Errorf("challenge = %q, want %q", used.Challenge, session.Challenge)
+	}
+	if _, err := h.useWebAuthnSession(ctx, token, webAuthnPurposeTwoFA, "user-1"); err == nil {
+		t.Error("replayed session accepted")
+	}
+}
+
+func TestDeletePasskeyRejectsMalformedID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &AuthHandler{}
+
+	for _, id := range []string{"not base64!", "a", strings.Repeat("A", 2000)} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/passkeys/x", nil)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		c.Set("user_id", "user-1")
+
+		h.deletePasskeyHandler(c)
+		if w.Code != 400 {
+			t.Errorf("DELETE /passkeys/%.10s: status %d, want 400", id, w.Code)
+		}
+	}
+}
+
+// passkeyService — БД-сервис пользователя без passkey, сохраняющий новые
+type passkeyService struct {
+	auditService
+
+	user *api.UserResponse
+
+	savedMu sync.Mutex
+	saved   int
+}
+
+func (s *passkeyService) GetUserByID(ctx context.Context, req *api.UserIDRequest) (*api.UserResponse, error) {
+	return s.user, nil
+}
+
+func (s *passkeyService) ListWebAuthnCredentials(ctx context.Context, req *api.UserIDRequest) (*api.ListWebAuthnCredentialsResponse, error) {
+	return &api.ListWebAuthnCredentialsResponse{}, nil
+}
+
+func (s *passkeyService) CreateWebAuthnCredential(ctx context.Context, req *api.CreateWebAuthnCredentialRequest) (*api.WebAuthnCredentialResponse, error) {
+	s.savedMu.Lock()
+	defer s.savedMu.Unlock()
+	s.saved++
+	return &api.WebAuthnCredentialResponse{CredentialId: req.CredentialId, UserId: req.UserId, Name: req.Name}, nil
+}
+
+// Добавить passkey можно только с текущим паролем: одного access-токена недостаточно.
+// Неудачная попытка не гасит токен сессии регистрации
+func TestFinishPasskeyRegistrationRequiresPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+	const origin = "https://checklist.example"
+
+	hasher := password.NewHasher(password.Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	hash, err := hasher.Hash("current password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	passkeys, err := passkey.New("checklist.example", "Checklist", []string{origin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := &passkeyService{user: &api.UserResponse{Id: uuid.New().String(), Email: "ann@example.com", Password: hash, Status: userStatusActive}}
+	h := &AuthHandler{
+		grpcClient: newTestClient(t, service),
+		cache:      cache.NewMemoryCache(),
+		throttler:  ratelimit.NewThrottlerFromEnv(ratelimit.NewMemoryStore()),
+		hasher:     hasher,
+		passkeys:   passkeys,
+	}
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("user_id", service.user.Id) })
+	r.POST("/passkeys/register/begin", h.beginPasskeyRegistrationHandler)
+	r.POST("/passkeys/register/finish", h.finishPasskeyRegistrationHandler)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/passkeys/register/begin", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("begin: status = %d, body: %s", w.Code, w.Body.String())
+	}
+	var begin struct {
+		Options      protocol.CredentialCreation `json:"options"`
+		SessionToken string                      `json:"session_token"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &begin); err != nil {
+		t.Fatal(err)
+	}
+	authenticator, err := softauthn.New(origin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credential, err := authenticator.Register(&begin.Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		currentPassword string
+		wantStatus      int
+		wantSaved       int
+	}{
+		{"no password", "", http.StatusBadRequest, 0},
+		{"wrong password", "wrong password", http.StatusUnauthorized, 0},
+		{"current password", "current password", http.StatusCreated, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(gin.H{
+				"session_token":    begin.SessionToken,
+				"credential":       json.RawMessage(credential),
+				"current_password": tt.currentPassword,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/passkeys/register/finish", strings.NewReader(string(body))))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body: %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			service.savedMu.Lock()
+			defer service.savedMu.Unlock()
+			if service.saved != tt.wantSaved {
+				t.Errorf("saved passkeys = %d, want %d", service.saved, tt.wantSaved)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Назначение токена сброса пароля в email_tokens
+const emailTokenPurposePasswordReset = "password_reset"
+
+// Время жизни ссылки для сброса пароля
+const passwordResetTokenTTL = time.Hour
+
+// forgotPasswordHandler отправляет ссылку для сброса пароля. Ответ не зависит от того,
+// зарегистрирован ли email: письмо отправляется в фоне, чтобы не выдавать это и по времени ответа
+func (h *AuthHandler) forgotPasswordHandler(c *gin.Context) {
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Email == "" {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	go h.sendPasswordResetEmail(req.Email)
+
+	c.JSON(202, gin.H{"message": "If the email is registered, a password reset link has been sent"})
+}
+
+// resetPasswordHandler устанавливает новый пароль по токену из письма и завершает все сессии
+func (h *AuthHandler) resetPasswordHandler(c *gin.Context) {
+	var req struct {
+		Token       string `json:"token"`
+		NewPassword string `json:"new_password"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Token == "" {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	// Email владельца токена нужен для проверки пароля; сам токен гасится только при сбросе
+	userResp, err := h.grpcClient.GetUserByEmailToken(context.Background(), emailTokenPurposePasswordReset, hashToken(req.Token))
+	if status.Code(err) == codes.NotFound {
+		c.JSON(400, gin.H{"error": "Invalid or expired reset token"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to check reset token"})
+		return
+	}
+	if !h.checkPasswordPolicy(c, req.NewPassword, userResp.Email) {
+		return
+	}
+
+	// Хэшируем пароль
+	hashedPassword, err := h.hasher.Hash(req.NewPassword)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	userID, err := h.grpcClient.ResetPassword(context.Background(), hashToken(req.Token), hashedPassword)
+	if status.Code(err) == codes.NotFound {
+		c.JSON(400, gin.H{"error": "Invalid or expired reset token"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	// Тот, кто знал старый пароль, не должен остаться в системе
+	if err := h.revokeAllSessions(loginContext(userID), userID); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+	recordAuditAs(h.grpcClient, c, userID, auditActionPasswordReset, userID, true)
+
+	c.JSON(200, gin.H{"message": "Password has been reset"})
+}
+
+// changePasswordHandler меняет пароль текущего пользователя. Требует текущий пароль
+// и TOTP-код, если включена 2FA. Все сессии, кроме текущей, завершаются
+func (h *AuthHandler) changePasswordHandler(c *gin.Context) {
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+		Code            string `json:"code"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.CurrentPassword == "" {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	claims := c.MustGet("token_claims").(jwt.MapClaims)
+	currentSessionID, _ := claims["sid"].(string)
+
+	userResp, err := h.grpcClient.GetUserByID(userContext(c), userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get user"})
+		return
+	}
+
+	// Повторная аутентификация: украденного access-токена недостаточно для смены пароля
+	if !h.reauthenticate(c, userResp, req.CurrentPassword, req.Code) {
+		return
+	}
+
+	if !h.checkPasswordPolicy(c, req.NewPassword, userResp.Email) {
+		return
+	}
+
+	// Запоминаем сессии до смены пароля, чтобы отозвать их access-токены
+	sessions, err := h.grpcClient.ListSessions(userContext(c), userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get sessions"})
+		return
+	}
+
+	// Хэшируем пароль
+	hashedPassword, err := h.hasher.Hash(req.NewPassword)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	if err := h.grpcClient.UpdatePassword(userContext(c), userID, hashedPassword, currentSessionID); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to update password"})
+		return
+	}
+
+	for _, session := range sessions.Sessions {
+		if session.Id == currentSessionID {
+			continue
+		}
+		if err := h.cache.Set(c.Request.Context(), revokedSessionKeyPrefix+session.Id, 1, accessTokenTTL); err != nil {
+			c.JSON(500, gin.H{"error": "Failed to revoke sessions"})
+			return
+		}
+	}
+
+	recordOwnAudit(h.grpcClient, c, auditActionPasswordChange, true)
+
+	c.JSON(200, gin.H{"message": "Password has been changed"})
+}
+
+// reauthenticate проверяет текущий пароль и TOTP-код (если включена 2FA) перед
+// чувствительным действием. При ошибке отвечает клиенту и возвращает false
+func (h *AuthHandler) reauthenticate(c *gin.Context, userResp *api.UserResponse, currentPassword, code string) bool {
+	if h.throttled(c, throttleReauthPrefix+userResp.Id) {
+		return false
+	}
+	ok, _, err := h.hasher.Verify(currentPassword, userResp.Password)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to verify password"})
+		return false
+	}
+	if !ok {
+		h.recordFailure(c, throttleReauthPrefix+userResp.Id)
+		recordOwnAudit(h.grpcClient, c, auditActionReauthenticate, false)
+		c.JSON(401, gin.H{"error": "Invalid current password"})
+		return false
+	}
+	if userResp.TwofaEnabled {
+		if code == "" {
+			c.JSON(401, gin.H{"error": "2FA code is required"})
+			return false
+		}
+		valid, err := h.checkTOTPCode(userContext(c), userResp.Id, userResp.TwofaSecret, code)
+		if err != nil {
+			c.JSON(500, gin.H{"error": "Failed to check 2FA code"})
+			return false
+		}
+		if !valid {
+			h.recordFailure(c, throttleReauthPrefix+userResp.Id)
+			recordOwnAudit(h.grpcClient, c, auditActionReauthenticate, false)
+			c.JSON(401, gin.H{"error": "Invalid 2FA code"})
+			return false
+		}
+	}
+	h.recordSuccess(c, throttleReauthPrefix+userResp.Id)
+	return true
+}
+
+// checkPasswordPolicy проверяет новый пароль и при нарушениях отвечает 400 со списком причин
+func (h *AuthHandler) checkPasswordPolicy(c *gin.Context, newPassword, email string) bool {
+	violations := h.passwordPolicy.Validate(newPassword, email)
+	if len(violations) == 0 {
+		return true
+	}
+
+	c.JSON(400, gin.H{
+		"error":      "Password does not meet requirements",
+		"violations": violations,
+	})
+	return false
+}
+
+// sendPasswordResetEmail создаёт токен сброса и отправляет ссылку, если пользователь существует
+func (h *AuthHandler) sendPasswordResetEmail(email string) {
+	ctx := context.Background()
+
+	userResp, err := h.grpcClient.GetUserByEmail(ctx, email)
+	if status.Code(err) == codes.NotFound {
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get user for password reset: %v", err)
+		return
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		log.Printf("Failed to generate password reset token: %v", err)
+		return
+	}
+	expiresAt := time.Now().Add(passwordResetTokenTTL)
+	if err := h.grpcClient.CreateEmailToken(loginContext(userResp.Id), userResp.Id, emailTokenPurposePasswordReset, hashToken(token), expiresAt); err != nil {
+		log.Printf("Failed to save password reset token: %v", err)
+		return
+	}
+
+	link := frontendURL("/password/reset", token)
+	body := "To reset your password, open the link below. It expires in 1 hour and can be used once.\n\n" +
+		link + "\n\nIf you did not request a password reset, ignore this email."
+	if err := h.mailer.Send(ctx, userResp.Email, "Password reset", body); err != nil {
+		log.Printf("Failed to send password reset email: %v", err)
+	}
+}
+
+// appURL строит ссылку на маршрут API (APP_BASE_URL) с токеном в параметре token
+func appURL(path, token string) string {
+	base := strings.TrimSuffix(os.Getenv("APP_BASE_URL"), "/")
+	if base == "" {
+		base = "http://localhost:8080"
+	}
+	return base + path + "?token=" + url.QueryEscape(token)
+}
+
+// frontendURL строит ссылку на страницу веб-приложения (FRONTEND_BASE_URL, по умолчанию
+// APP_BASE_URL) с токеном в параметре token. Страница сама отправляет токен в API
+// POST-запросом, поэтому открытие ссылки почтовым сканером токен не погашает
+func frontendURL(path, token string) string {
+	base := strings.TrimSuffix(os.Getenv("FRONTEND_BASE_URL"), "/")
+	if base == "" {
+		return appURL(path, token)
+	}
+	return base + path + "?token=" + url.QueryEscape(token)
+}
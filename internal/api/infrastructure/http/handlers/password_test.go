@@ -0,0 +1,31 @@
+package handlers
+
+import "testing"
+
+func TestEmailLinks(t *testing.T) {
+	tests := []struct {
+		name        string
+		appBase     string
+		frontend    string
+		wantPage    string
+		wantAPIRoot string
+	}{
+		{"defaults", "", "", "http://localhost:8080/password/reset?token=a%2Bb", "http://localhost:8080/password/reset?token=a%2Bb"},
+		{"app only", "https://api.example.com/", "", "https://api.example.com/password/reset?token=a%2Bb", "https://api.example.com/password/reset?token=a%2Bb"},
+		{"separate frontend", "https://api.example.com", "https://app.example.com/", "https://app.example.com/password/reset?token=a%2Bb", "https://api.example.com/password/reset?token=a%2Bb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_BASE_URL", tt.appBase)
+			t.Setenv("FRONTEND_BASE_URL", tt.frontend)
+
+			// Страницы из писем открываются в веб-приложении, маршруты API — на APP_BASE_URL
+			if got := frontendURL("/password/reset", "a+b"); got != tt.wantPage {
+				t.Errorf("frontendURL() = %q, want %q", got, tt.wantPage)
+			}
+			if got := appURL("/password/reset", "a+b"); got != tt.wantAPIRoot {
+				t.Errorf("appURL() = %q, want %q", got, tt.wantAPIRoot)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/http/middleware"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Префикс персональных токенов: по нему authMiddleware отличает их от JWT,
+// а сканеры секретов — находят в коде
+const personalAccessTokenPrefix = "clp_"
+
+const maxPersonalAccessTokenNameLength = 100
+
+// Время использования токена (last_used_at) обновляется не чаще раза в personalAccessTokenUseInterval,
+// чтобы не писать в БД на каждый запрос. Отметка хранится в кэше по ключу с хэшем токена
+const (
+	personalAccessTokenUseInterval   = time.Minute
+	personalAccessTokenUsedKeyPrefix = "pat_used:" // + хэш токена
+)
+
+// createPersonalAccessTokenHandler создаёт персональный токен. Токен возвращается
+// только в этом ответе, в БД хранится его хэш
+func (h *AuthHandler) createPersonalAccessTokenHandler(c *gin.Context) {
+	var req struct {
+		Name          string   `json:"name"`
+		Scopes        []string `json:"scopes"`
+		ExpiresInDays int      `json:"expires_in_days"` // 0 — бессрочный
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > maxPersonalAccessTokenNameLength {
+		c.JSON(400, gin.H{"error": "Name is required and must be at most 100 characters"})
+		return
+	}
+	if req.ExpiresInDays < 0 {
+		c.JSON(400, gin.H{"error": "expires_in_days must not be negative"})
+		return
+	}
+
+	var scopes []string
+	for _, scope := range req.Scopes {
+		if !personalAccessTokenScopes[scope] {
+			c.JSON(400, gin.H{"error": "Unknown scope: " + scope})
+			return
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		c.JSON(400, gin.H{"error": "At least one scope is required"})
+		return
+	}
+
+	var expiresAt time.Time
+	if req.ExpiresInDays > 0 {
+		expiresAt = time.Now().AddDate(0, 0, req.ExpiresInDays)
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to generate token"})
+		return
+	}
+	token := personalAccessTokenPrefix + secret
+
+	userID := c.GetString("user_id")
+	resp, err := h.grpcClient.CreatePersonalAccessToken(userContext(c), userID, req.Name, hashToken(token), scopes, expiresAt)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to create token"})
+		return
+	}
+	recordOwnAudit(h.grpcClient, c, auditActionTokenCreate, true)
+
+	body := personalAccessTokenJSON(resp)
+	body["token"] = token
+	c.JSON(201, body)
+}
+
+// listPersonalAccessTokensHandler возвращает персональные токены пользователя (без самих токенов)
+func (h *AuthHandler) listPersonalAccessTokensHandler(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	resp, err := h.grpcClient.ListPersonalAccessTokens(userContext(c), userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to list tokens"})
+		return
+	}
+
+	tokens := make([]gin.H, 0, len(resp.Tokens))
+	for _, token := range resp.Tokens {
+		tokens = append(tokens, personalAccessTokenJSON(token))
+	}
+
+	c.JSON(200, tokens)
+}
+
+// deletePersonalAccessTokenHandler отзывает персональный токен
+func (h *AuthHandler) deletePersonalAccessTokenHandler(c *gin.Context) {
+	userID := c.GetString("user_id")
+	tokenID := c.Param("id")
+	if _, err := uuid.Parse(tokenID); err != nil {
+		c.JSON(404, gin.H{"error": "Token not found"})
+		return
+	}
+
+	err := h.grpcClient.RevokePersonalAccessToken(userContext(c), userID, tokenID)
+	if status.Code(err) == codes.NotFound {
+		c.JSON(404, gin.H{"error": "Token not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to revoke token"})
+		return
+	}
+	recordOwnAudit(h.grpcClient, c, auditActionTokenRevoke, true)
+
+	c.Status(204)
+}
+
+// authenticatePersonalAccessToken проверяет персональный токен в authMiddleware
+// и сохраняет в контексте владельца и scopes токена
+func (h *AuthHandler) authenticatePersonalAccessToken(c *gin.Context, token string) {
+	tokenHash := hashToken(token)
+	usedKey := personalAccessTokenUsedKeyPrefix + tokenHash
+	// Если кэш недоступен, время использования обновляется как обычно
+	used, err := h.cache.Get(c.Request.Context(), usedKey)
+	if err != nil {
+		log.Printf("Failed to check personal access token use: %v", err)
+	}
+	recordUse := used == ""
+
+	resp, err := h.grpcClient.AuthenticatePersonalAccessToken(context.Background(), tokenHash, recordUse)
+	if status.Code(err) == codes.NotFound {
+		c.JSON(401, gin.H{"error": "Invalid token"})
+		c.Abort()
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to check token"})
+		c.Abort()
+		return
+	}
+
+	// Отметка ставится только для действующих токенов: подбор не заполняет кэш
+	if recordUse {
+		if err := h.cache.Set(c.Request.Context(), usedKey, 1, personalAccessTokenUseInterval); err != nil {
+			log.Printf("Failed to remember personal access token use: %v", err)
+		}
+	}
+
+	c.Set("user_id", resp.UserId)
+	c.Set(middleware.PrincipalKey, &middleware.Principal{
+		UserID: resp.UserId,
+		Scopes: resp.Scopes,
+	})
+	c.Next()
+}
+
+// personalAccessTokenJSON преобразует токен в ответ API (null — срок или использование не заданы)
+func personalAccessTokenJSON(token *api.PersonalAccessTokenResponse) gin.H {
+	body := gin.H{
+		"id":           token.Id,
+		"name":         token.Name,
+		"scopes":       token.Scopes,
+		"created_at":   time.Unix(token.CreatedAt, 0).UTC(),
+		"expires_at":   nil,
+		"last_used_at": nil,
+	}
+	if token.ExpiresAt != 0 {
+		body["expires_at"] = time.Unix(token.ExpiresAt, 0).UTC()
+	}
+	if token.LastUsedAt != 0 {
+		body["last_used_at"] = time.Unix(token.LastUsedAt, 0).UTC()
+	}
+	return body
+}
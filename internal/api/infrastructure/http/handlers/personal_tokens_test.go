@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/cache"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// personalTokenService принимает один токен и запоминает, просили ли отметить его использование
+type personalTokenService struct {
+	api.UnimplementedChecklistServiceServer
+
+	tokenHash string
+
+	mu         sync.Mutex
+	recordUses []bool
+}
+
+func (s *personalTokenService) AuthenticatePersonalAccessToken(ctx context.Context, req *api.AuthenticatePersonalAccessTokenRequest) (*api.PersonalAccessTokenResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recordUses = append(s.recordUses, req.RecordUse)
+	if req.TokenHash != s.tokenHash {
+		return nil, status.Error(codes.NotFound, "token not found")
+	}
+	return &api.PersonalAccessTokenResponse{Id: "token-1", UserId: "user-1", Scopes: []string{"tasks:read"}}, nil
+}
+
+// Время использования персонального токена пишется в БД не чаще раза в минуту;
+// неверные токены отметок в кэше не оставляют
+func TestPersonalAccessTokenUseIsThrottled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	valid := personalAccessTokenPrefix + "valid"
+	service := &personalTokenService{tokenHash: hashToken(valid)}
+	h := &AuthHandler{grpcClient: newTestClient(t, service), cache: cache.NewMemoryCache()}
+	r := gin.New()
+	r.GET("/tasks", func(c *gin.Context) {
+		h.authenticatePersonalAccessToken(c, c.GetHeader("X-Token"))
+	}, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name           string
+		token          string
+		wantStatus     int
+		wantRecordUses []bool
+	}{
+		{"first use", valid, http.StatusOK, []bool{true}},
+		{"use within a minute", valid, http.StatusOK, []bool{true, false}},
+		{"invalid token", personalAccessTokenPrefix + "invalid", http.StatusUnauthorized, []bool{true, false, true}},
+		{"invalid token again", personalAccessTokenPrefix + "invalid", http.StatusUnauthorized, []bool{true, false, true, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			req.Header.Set("X-Token", tt.token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body: %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			service.mu.Lock()
+			defer service.mu.Unlock()
+			if !slices.Equal(service.recordUses, tt.wantRecordUses) {
+				t.Errorf("record_use flags = %v, want %v", service.recordUses, tt.wantRecordUses)
+			}
+		})
+	}
+}
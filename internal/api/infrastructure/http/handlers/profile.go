@@ -1,10 +1,10 @@
 package handlers
 
 import (
-	"context"
-
 	"github.com/gin-gonic/gin"
 	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/grpc_client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type ProfileHandler struct {
@@ -24,22 +24,23 @@ func (h *ProfileHandler) getProfileHandler(c *gin.Context) {
 	}
 
 	// Отправляем запрос в БД-сервис через gRPC
-	userResp, err := h.grpcClient.GetUserByEmail(context.Background(), "placeholder-email@example.com") // Заменим позже на поиск по userID
-	if err != nil {
-		c.JSON(500, gin.H{"error": "Failed to get user profile"})
+	userResp, err := h.grpcClient.GetUserByID(userContext(c), userID.(string))
+	if status.Code(err) == codes.NotFound {
+		c.JSON(404, gin.H{"error": "User not found"})
 		return
 	}
-	if userResp == nil {
-		c.JSON(404, gin.H{"error": "User not found"})
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get user profile"})
 		return
 	}
 
 	c.JSON(200, gin.H{
-		"id":          userResp.Id,
-		"email":       userResp.Email,
-		"avatar":      userResp.Avatar,
-		"description": userResp.Description,
-		"socials":     userResp.Socials,
+		"id":             userResp.Id,
+		"email":          userResp.Email,
+		"avatar":         userResp.Avatar,
+		"description":    userResp.Description,
+		"socials":        userResp.Socials,
+		"email_verified": userResp.EmailVerified,
 	})
 }
 
@@ -62,11 +63,12 @@ func (h *ProfileHandler) updateProfileHandler(c *gin.Context) {
 	}
 
 	// Отправляем запрос в БД-сервис через gRPC
-	userResp, err := h.grpcClient.UpdateProfile(context.Background(), userID.(string), req.Avatar, req.Description, req.Socials)
+	userResp, err := h.grpcClient.UpdateProfile(userContext(c), userID.(string), req.Avatar, req.Description, req.Socials)
 	if err != nil {
 		c.JSON(500, gin.H{"error": "Failed to update profile"})
 		return
 	}
+	recordOwnAudit(h.grpcClient, c, auditActionProfileUpdate, true)
 
 	c.JSON(200, gin.H{
 		"id":          userResp.Id,
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Права доступа (scopes)
+const (
+	scopeTasksRead    = "tasks:read"
+	scopeTasksWrite   = "tasks:write"
+	scopeProfileRead  = "profile:read"
+	scopeProfileWrite = "profile:write"
+)
+
+// Роли пользователей
+const (
+	roleUser  = "user"
+	roleAdmin = "admin"
+)
+
+// Scopes, которые можно выдать персональному токену
+var personalAccessTokenScopes = map[string]bool{
+	scopeTasksRead:    true,
+	scopeTasksWrite:   true,
+	scopeProfileRead:  true,
+	scopeProfileWrite: true,
+}
+
+// sessionScopes — права access-токена, выданного при входе. Административные маршруты
+// проверяют роль, а не scope, поэтому набор одинаков для всех ролей
+var sessionScopes = []string{scopeTasksRead, scopeTasksWrite, scopeProfileRead, scopeProfileWrite}
+
+// claimRoles читает claim "roles". В токенах, выпущенных до появления ролей, его нет
+func claimRoles(claims jwt.MapClaims) []string {
+	values, ok := claims["roles"].([]interface{})
+	if !ok {
+		return []string{roleUser}
+	}
+	roles := make([]string, 0, len(values))
+	for _, value := range values {
+		if role, ok := value.(string); ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// claimScopes читает claim "scope" (через пробел, как в OAuth 2.0)
+func claimScopes(claims jwt.MapClaims) []string {
+	scope, ok := claims["scope"].(string)
+	if !ok {
+		return sessionScopes
+	}
+	return strings.Fields(scope)
+}
+
+// claimActor читает claim "act": ID администратора, действующего от имени пользователя
+func claimActor(claims jwt.MapClaims) string {
+	act, ok := claims["act"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	actorID, _ := act["sub"].(string)
+	return actorID
+}
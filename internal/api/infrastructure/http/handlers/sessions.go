@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// listSessionsHandler возвращает активные сессии (устройства) пользователя
+func (h *AuthHandler) listSessionsHandler(c *gin.Context) {
+	userID := c.GetString("user_id")
+	claims := c.MustGet("token_claims").(jwt.MapClaims)
+	currentSID, _ := claims["sid"].(string)
+
+	resp, err := h.grpcClient.ListSessions(userContext(c), userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to list sessions"})
+		return
+	}
+
+	sessions := make([]gin.H, 0, len(resp.Sessions))
+	for _, session := range resp.Sessions {
+		sessions = append(sessions, gin.H{
+			"id":           session.Id,
+			"device_name":  session.DeviceName,
+			"user_agent":   session.UserAgent,
+			"ip":           session.Ip,
+			"created_at":   time.Unix(session.CreatedAt, 0).UTC(),
+			"last_seen_at": time.Unix(session.LastSeenAt, 0).UTC(),
+			"current":      session.Id == currentSID,
+		})
+	}
+
+	c.JSON(200, sessions)
+}
+
+// deleteSessionHandler завершает сессию пользователя на другом (или текущем) устройстве
+func (h *AuthHandler) deleteSessionHandler(c *gin.Context) {
+	userID := c.GetString("user_id")
+	sessionID := c.Param("id")
+	if _, err := uuid.Parse(sessionID); err != nil {
+		c.JSON(404, gin.H{"error": "Session not found"})
+		return
+	}
+
+	found, err := h.revokeSession(userContext(c), userID, sessionID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+	if !found {
+		c.JSON(404, gin.H{"error": "Session not found"})
+		return
+	}
+	recordOwnAudit(h.grpcClient, c, auditActionSessionRevoke, true)
+
+	c.Status(204)
+}
+
+// revokeSession отзывает refresh-токены сессии и все выпущенные в ней access-токены.
+// Возвращает false, если у пользователя нет такой активной сессии. ctx — контекст вызова
+// БД-сервиса (userContext)
+func (h *AuthHandler) revokeSession(ctx context.Context, userID, sessionID string) (bool, error) {
+	err := h.grpcClient.RevokeRefreshFamily(ctx, userID, sessionID)
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := h.cache.Set(ctx, revokedSessionKeyPrefix+sessionID, 1, accessTokenTTL); err != nil {
+		return true, err
+	}
+	return true, nil
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
-	"context"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/grpc_client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type TaskHandler struct {
@@ -32,7 +34,7 @@ func (h *TaskHandler) createTaskHandler(c *gin.Context) {
 	}
 
 	// Отправляем запрос в БД-сервис через gRPC
-	resp, err := h.grpcClient.CreateTask(context.Background(), req.Title, req.Content, userID.(string))
+	resp, err := h.grpcClient.CreateTask(userContext(c), req.Title, req.Content, userID.(string))
 	if err != nil {
 		c.JSON(500, gin.H{"error": "Failed to create task"})
 		return
@@ -56,7 +58,7 @@ func (h *TaskHandler) listTasksHandler(c *gin.Context) {
 	}
 
 	// Отправляем запрос в БД-сервис через gRPC
-	resp, err := h.grpcClient.ListTasks(context.Background(), userID.(string))
+	resp, err := h.grpcClient.ListTasks(userContext(c), userID.(string))
 	if err != nil {
 		c.JSON(500, gin.H{"error": "Failed to list tasks"})
 		return
@@ -72,8 +74,18 @@ func (h *TaskHandler) deleteTaskHandler(c *gin.Context) {
 		return
 	}
 
-	// Отправляем запрос в БД-сервис через gRPC
-	if err := h.grpcClient.DeleteTask(context.Background(), id); err != nil {
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(404, gin.H{"error": "Task not found"})
+		return
+	}
+
+	// Отправляем запрос в БД-сервис через gRPC; чужая задача для него не существует
+	err := h.grpcClient.DeleteTask(userContext(c), id)
+	if status.Code(err) == codes.NotFound {
+		c.JSON(404, gin.H{"error": "Task not found"})
+		return
+	}
+	if err != nil {
 		c.JSON(500, gin.H{"error": "Failed to delete task"})
 		return
 	}
@@ -88,8 +100,17 @@ func (h *TaskHandler) markTaskDoneHandler(c *gin.Context) {
 		return
 	}
 
-	// Отправляем запрос в БД-сервис через gRPC
-	resp, err := h.grpcClient.MarkTaskDone(context.Background(), id)
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(404, gin.H{"error": "Task not found"})
+		return
+	}
+
+	// Отправляем запрос в БД-сервис через gRPC; чужая задача для него не существует
+	resp, err := h.grpcClient.MarkTaskDone(userContext(c), id)
+	if status.Code(err) == codes.NotFound {
+		c.JSON(404, gin.H{"error": "Task not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(500, gin.H{"error": "Failed to mark task as done"})
 		return
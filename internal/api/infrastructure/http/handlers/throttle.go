@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"log"
+	"math"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Префиксы идентификаторов для Throttler, кроме входа по email
+const (
+	throttleTwoFAPrefix  = "2fa:"    // + user_id: проверка TOTP-кодов и кодов восстановления
+	throttleReauthPrefix = "reauth:" // + user_id: повторный ввод пароля
+)
+
+// throttled отвечает 429 с Retry-After, если попытки для account с IP клиента временно запрещены
+func (h *AuthHandler) throttled(c *gin.Context, account string) bool {
+	wait, err := h.throttler.Check(c.Request.Context(), account, c.ClientIP())
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to check failed attempts"})
+		return true
+	}
+	if wait <= 0 {
+		return false
+	}
+
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	c.JSON(429, gin.H{"error": "Too many failed attempts, try again later"})
+	return true
+}
+
+// recordFailure учитывает неудачную попытку; ошибка хранилища не должна менять ответ клиенту
+func (h *AuthHandler) recordFailure(c *gin.Context, account string) {
+	if err := h.throttler.Failure(c.Request.Context(), account, c.ClientIP()); err != nil {
+		log.Printf("Failed to record failed attempt: %v", err)
+	}
+}
+
+// recordSuccess сбрасывает счётчик неудач аккаунта
+func (h *AuthHandler) recordSuccess(c *gin.Context, account string) {
+	if err := h.throttler.Success(c.Request.Context(), account); err != nil {
+		log.Printf("Failed to reset failed attempts: %v", err)
+	}
+}
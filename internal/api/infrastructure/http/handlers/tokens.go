@@ -0,0 +1,336 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+)
+
+// Типы токенов (claim "typ")
+const (
+	tokenTypeAccess    = "access"
+	tokenTypeRefresh   = "refresh"
+	tokenTypeChallenge = "mfa_challenge"
+
+	tokenTypeWebAuthnSession = "webauthn_session"
+)
+
+const (
+	accessTokenTTL    = 15 * time.Minute   // 15 минут для Access-токена
+	refreshTokenTTL   = 7 * 24 * time.Hour // 7 дней для Refresh-токена
+	challengeTokenTTL = 5 * time.Minute    // между вводом пароля и кода 2FA
+
+	impersonationTokenTTL = 10 * time.Minute // токен администратора, действующего от имени пользователя
+)
+
+// SignedTokenMaxTTL — наибольшее время жизни токенов, подписываемых набором ключей
+// (access-токены и токены имперсонации). Refresh-токены подписываются REFRESH_TOKEN_SECRET
+const SignedTokenMaxTTL = max(accessTokenTTL, impersonationTokenTTL)
+
+// Префикс ключа кэша с jti использованного challenge-токена
+const usedChallengeTokenKeyPrefix = "used_challenge_token:"
+
+// Префикс ключа кэша с сессией имперсонации: + sid, значение — ID пользователя
+const impersonationKeyPrefix = "impersonation:"
+
+// issueTokens заводит новую сессию (семейство refresh-токенов), выпускает пару
+// access/refresh токенов и отправляет её клиенту. Имя устройства клиент может
+// передать в заголовке X-Device-Name. Вход отменяет запланированное удаление аккаунта
+func (h *AuthHandler) issueTokens(c *gin.Context, userResp *api.UserResponse) {
+	if userResp.DeletionScheduledAt != 0 {
+		cancelled, err := h.grpcClient.CancelAccountDeletion(loginContext(userResp.Id), userResp.Id)
+		if err != nil {
+			c.JSON(500, gin.H{"error": "Failed to cancel account deletion"})
+			return
+		}
+		h.forgetUserStatus(c.Request.Context(), userResp.Id)
+		if cancelled {
+			recordAuditAs(h.grpcClient, c, userResp.Id, auditActionDeletionCancel, userResp.Id, true)
+		}
+	}
+
+	familyID := uuid.New().String()
+	jti := uuid.New().String()
+	expiresAt := time.Now().Add(refreshTokenTTL)
+
+	err := h.grpcClient.CreateRefreshFamily(loginContext(userResp.Id), familyID, userResp.Id, jti, expiresAt,
+		c.GetHeader("X-Device-Name"), c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to create session"})
+		return
+	}
+	recordAuditAs(h.grpcClient, c, userResp.Id, auditActionLogin, userResp.Id, true)
+
+	h.sendTokens(c, userResp.Id, userResp.Role, familyID, jti, expiresAt)
+}
+
+// sendTokens подписывает access-токен и refresh-токен с заданным jti и отправляет их клиенту
+func (h *AuthHandler) sendTokens(c *gin.Context, userID, role, familyID, jti string, refreshExpiresAt time.Time) {
+	// Генерируем JWT-токен
+	accessToken, err := h.generateAccessToken(userID, role, familyID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to generate access token"})
+		return
+	}
+
+	// Генерируем Refresh-токен
+	refreshToken, err := generateRefreshToken(userID, familyID, jti, refreshExpiresAt)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to generate refresh token"})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"token_type":    "Bearer",
+		"expires_in":    int(accessTokenTTL.Seconds()),
+	})
+}
+
+// refreshTokenHandler обменивает refresh-токен на новую пару токенов.
+// Старый refresh-токен становится недействительным; его повторное использование
+// отзывает всё семейство, т.е. все токены, выпущенные после этого входа
+func (h *AuthHandler) refreshTokenHandler(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	claims, err := parseToken(req.RefreshToken, os.Getenv("REFRESH_TOKEN_SECRET"), tokenTypeRefresh)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+	userID, _ := claims["user_id"].(string)
+	familyID, _ := claims["fid"].(string)
+	jti, _ := claims["jti"].(string)
+	if userID == "" || familyID == "" || jti == "" {
+		c.JSON(401, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	newJTI := uuid.New().String()
+	expiresAt := time.Now().Add(refreshTokenTTL)
+	resp, err := h.grpcClient.RotateRefreshToken(context.Background(), familyID, jti, newJTI, expiresAt)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to refresh token"})
+		return
+	}
+	if resp.ReuseDetected {
+		c.JSON(401, gin.H{"error": "Refresh token reuse detected, session revoked"})
+		return
+	}
+	if !resp.Rotated || resp.UserId != userID {
+		c.JSON(401, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	// Роль и статус читаются заново, чтобы их изменение вступало в силу при обновлении токена
+	userResp, err := h.grpcClient.GetUserByID(loginContext(userID), userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get user"})
+		return
+	}
+	if userResp.Status != userStatusActive {
+		c.JSON(403, gin.H{"error": accountStatusError(userResp.Status)})
+		return
+	}
+
+	h.sendTokens(c, userID, userResp.Role, familyID, newJTI, expiresAt)
+}
+
+// generateAccessToken выпускает access-токен. sid — семейство refresh-токенов (сессия),
+// jti — идентификатор токена для отзыва, roles и scope — права для проверки на маршрутах. Если настроен набор ключей, токен подписывается
+// активным ключом (RS256/EdDSA) с kid в заголовке, иначе HS256 с JWT_SECRET
+func (h *AuthHandler) generateAccessToken(userID, role, familyID string) (string, error) {
+	if role == "" {
+		role = roleUser
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id": userID,
+		"typ":     tokenTypeAccess,
+		"jti":     uuid.New().String(),
+		"sid":     familyID,
+		"roles":   []string{role},
+		"scope":   strings.Join(sessionScopes, " "),
+		"iat":     issuedAt(now),
+		"exp":     now.Add(accessTokenTTL).Unix(),
+	}
+	return h.signAccessToken(claims)
+}
+
+// generateImpersonationToken выпускает access-токен пользователя userID для администратора actorID.
+// Claim act (RFC 8693) называет администратора; refresh-токен не выдаётся. sessionID не связан
+// с семейством refresh-токенов: такую сессию отзывает только DELETE /admin/impersonations/:sid
+// через revoked_session:, не затрагивая сессии пользователя
+func (h *AuthHandler) generateImpersonationToken(userID, role, actorID, sessionID string) (string, error) {
+	if role == "" {
+		role = roleUser
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id": userID,
+		"typ":     tokenTypeAccess,
+		"jti":     uuid.New().String(),
+		"sid":     sessionID,
+		"act":     map[string]string{"sub": actorID},
+		"roles":   []string{role},
+		"scope":   strings.Join(sessionScopes, " "),
+		"iat":     issuedAt(now),
+		"exp":     now.Add(impersonationTokenTTL).Unix(),
+	}
+	return h.signAccessToken(claims)
+}
+
+// signAccessToken подписывает claims access-токена активным ключом набора или HS256 с JWT_SECRET
+func (h *AuthHandler) signAccessToken(claims jwt.MapClaims) (string, error) {
+	if h.keys != nil {
+		return h.keys.Sign(claims)
+	}
+
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return "", fmt.Errorf("JWT_SECRET not set in .env")
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+}
+
+// accessTokenKeyfunc возвращает ключ проверки access-токена: открытый ключ по kid
+// из набора ключей или JWT_SECRET, если набор не настроен
+func (h *AuthHandler) accessTokenKeyfunc(token *jwt.Token) (interface{}, error) {
+	if h.keys != nil {
+		return h.keys.Keyfunc(token)
+	}
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+	}
+	return []byte(os.Getenv("JWT_SECRET")), nil
+}
+
+// jwksHandler публикует открытые ключи проверки access-токенов (RFC 7517)
+func (h *AuthHandler) jwksHandler(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	if h.keys == nil {
+		c.JSON(200, gin.H{"keys": []interface{}{}})
+		return
+	}
+	c.JSON(200, h.keys.JWKS())
+}
+
+// generateRefreshToken выпускает refresh-токен семейства familyID с идентификатором jti
+func generateRefreshToken(userID, familyID, jti string, expiresAt time.Time) (string, error) {
+	secret := os.Getenv("REFRESH_TOKEN_SECRET")
+	if secret == "" {
+		return "", fmt.Errorf("REFRESH_TOKEN_SECRET not set in .env")
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"typ":     tokenTypeRefresh,
+		"fid":     familyID,
+		"jti":     jti,
+		"exp":     expiresAt.Unix(),
+	})
+
+	return token.SignedString([]byte(secret))
+}
+
+// generateChallengeToken выпускает короткоживущий токен, подтверждающий,
+// что пароль уже проверен и ожидается второй фактор. По jti токен гасится после входа
+func generateChallengeToken(userID string) (string, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return "", fmt.Errorf("JWT_SECRET not set in .env")
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"typ":     tokenTypeChallenge,
+		"jti":     uuid.New().String(),
+		"exp":     time.Now().Add(challengeTokenTTL).Unix(),
+	})
+
+	return token.SignedString([]byte(secret))
+}
+
+// parseChallengeToken проверяет challenge-токен и возвращает user_id и jti
+func parseChallengeToken(tokenString string) (string, string, error) {
+	claims, err := parseToken(tokenString, os.Getenv("JWT_SECRET"), tokenTypeChallenge)
+	if err != nil {
+		return "", "", err
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return "", "", fmt.Errorf("Invalid user_id in challenge token")
+	}
+	jti, ok := claims["jti"].(string)
+	if !ok || jti == "" {
+		return "", "", fmt.Errorf("Invalid jti in challenge token")
+	}
+
+	return userID, jti, nil
+}
+
+// useChallengeToken гасит challenge-токен после проверки второго фактора.
+// false — токен уже использован для входа
+func (h *AuthHandler) useChallengeToken(ctx context.Context, jti string) (bool, error) {
+	return h.cache.SetIfAbsent(ctx, usedChallengeTokenKeyPrefix+jti, 1, challengeTokenTTL)
+}
+
+// parseToken проверяет подпись HMAC-токена, срок действия и тип (claim "typ")
+func parseToken(tokenString, secret, typ string) (jwt.MapClaims, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("Token secret not set in .env")
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secret), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, fmt.Errorf("Invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] != typ {
+		return nil, fmt.Errorf("Invalid token type")
+	}
+
+	return claims, nil
+}
+
+// randomToken возвращает 32 случайных байта в base64url
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken возвращает SHA-256 одноразового токена: в БД хранятся только хэши
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
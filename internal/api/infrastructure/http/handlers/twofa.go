@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"image/png"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+// Размер QR-кода для приложения-аутентификатора (в пикселях)
+const qrCodeSize = 256
+
+// Количество кодов восстановления в наборе
+const recoveryCodeCount = 10
+
+// Длительность шага TOTP в секундах и допустимое расхождение часов в шагах
+const (
+	totpPeriod = 30
+	totpSkew   = 1
+)
+
+// setup2FAHandler генерирует новый TOTP-секрет (RFC 6238) и возвращает его вместе
+// с otpauth:// URI и QR-кодом. 2FA включается только после подтверждения кода в /2fa/verify
+func (h *AuthHandler) setup2FAHandler(c *gin.Context) {
+	// Получаем userID из контекста
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(401, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	userResp, err := h.grpcClient.GetUserByID(userContext(c), userID.(string))
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get user"})
+		return
+	}
+	if userResp.TwofaEnabled {
+		c.JSON(409, gin.H{"error": "2FA is already enabled"})
+		return
+	}
+
+	issuer := os.Getenv("TOTP_ISSUER")
+	if issuer == "" {
+		issuer = "Checklist"
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: userResp.Email,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to generate 2FA secret"})
+		return
+	}
+
+	// Сохраняем секрет, но не включаем 2FA до подтверждения кода
+	if _, err := h.grpcClient.UpdateTwoFA(userContext(c), userResp.Id, false, key.Secret()); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to save 2FA secret"})
+		return
+	}
+
+	img, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to generate QR code"})
+		return
+	}
+	var qr bytes.Buffer
+	if err := png.Encode(&qr, img); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to generate QR code"})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"secret":      key.Secret(),
+		"otpauth_url": key.URL(),
+		"qr_code":     "data:image/png;base64," + base64.StdEncoding.EncodeToString(qr.Bytes()),
+	})
+}
+
+// verify2FAHandler проверяет TOTP-код и включает 2FA для пользователя
+func (h *AuthHandler) verify2FAHandler(c *gin.Context) {
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	// Получаем userID из контекста
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(401, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	userResp, err := h.grpcClient.GetUserByID(userContext(c), userID.(string))
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get user"})
+		return
+	}
+	if userResp.TwofaEnabled {
+		c.JSON(409, gin.H{"error": "2FA is already enabled"})
+		return
+	}
+	if userResp.TwofaSecret == "" {
+		c.JSON(400, gin.H{"error": "2FA setup has not been started"})
+		return
+	}
+
+	if h.throttled(c, throttleTwoFAPrefix+userResp.Id) {
+		return
+	}
+	valid, err := h.checkTOTPCode(userContext(c), userResp.Id, userResp.TwofaSecret, req.Code)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to check 2FA code"})
+		return
+	}
+	if !valid {
+		h.recordFailure(c, throttleTwoFAPrefix+userResp.Id)
+		c.JSON(401, gin.H{"error": "Invalid 2FA code"})
+		return
+	}
+	h.recordSuccess(c, throttleTwoFAPrefix+userResp.Id)
+
+	// Коды восстановления выдаются вместе с включением 2FA и показываются один раз
+	recoveryCodes, err := h.replaceRecoveryCodes(userContext(c), userResp.Id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to generate recovery codes"})
+		return
+	}
+
+	if _, err := h.grpcClient.UpdateTwoFA(userContext(c), userResp.Id, true, userResp.TwofaSecret); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to enable 2FA"})
+		return
+	}
+	recordOwnAudit(h.grpcClient, c, auditActionTwoFAEnable, true)
+
+	c.JSON(200, gin.H{
+		"message":        "2FA enabled",
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// regenerateRecoveryCodesHandler выдаёт новый набор кодов восстановления, старые перестают действовать
+func (h *AuthHandler) regenerateRecoveryCodesHandler(c *gin.Context) {
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	// Получаем userID из контекста
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(401, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	userResp, err := h.grpcClient.GetUserByID(userContext(c), userID.(string))
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get user"})
+		return
+	}
+	if !userResp.TwofaEnabled {
+		c.JSON(400, gin.H{"error": "2FA is not enabled"})
+		return
+	}
+
+	if h.throttled(c, throttleTwoFAPrefix+userResp.Id) {
+		return
+	}
+	valid, err := h.checkTOTPCode(userContext(c), userResp.Id, userResp.TwofaSecret, req.Code)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to check 2FA code"})
+		return
+	}
+	if !valid {
+		h.recordFailure(c, throttleTwoFAPrefix+userResp.Id)
+		c.JSON(401, gin.H{"error": "Invalid 2FA code"})
+		return
+	}
+	h.recordSuccess(c, throttleTwoFAPrefix+userResp.Id)
+
+	recoveryCodes, err := h.replaceRecoveryCodes(userContext(c), userResp.Id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to generate recovery codes"})
+		return
+	}
+	recordOwnAudit(h.grpcClient, c, auditActionRecoveryCodesRegen, true)
+
+	c.JSON(200, gin.H{"recovery_codes": recoveryCodes})
+}
+
+// login2FAHandler обменивает challenge-токен и TOTP-код (или код восстановления)
+// на пару access/refresh токенов
+func (h *AuthHandler) login2FAHandler(c *gin.Context) {
+	var req struct {
+		ChallengeToken string `json:"challenge_token"`
+		Code           string `json:"code"`
+		RecoveryCode   string `json:"recovery_code"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.ChallengeToken == "" || (req.Code == "" && req.RecoveryCode == "") {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	userID, challengeID, err := parseChallengeToken(req.ChallengeToken)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "Invalid or expired challenge token"})
+		return
+	}
+	// Токен гасится до проверки второго фактора, чтобы повторно предъявленный токен
+	// не расходовал коды восстановления и шаги TOTP. После неверного кода вход
+	// начинается заново с пароля
+	if !h.consumeChallengeToken(c, challengeID) {
+		return
+	}
+
+	userResp, err := h.grpcClient.GetUserByID(loginContext(userID), userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get user"})
+		return
+	}
+	if !userResp.TwofaEnabled {
+		c.JSON(401, gin.H{"error": "Invalid or expired challenge token"})
+		return
+	}
+
+	if h.throttled(c, throttleTwoFAPrefix+userResp.Id) {
+		return
+	}
+	if req.Code != "" {
+		valid, err := h.checkTOTPCode(loginContext(userResp.Id), userResp.Id, userResp.TwofaSecret, req.Code)
+		if err != nil {
+			c.JSON(500, gin.H{"error": "Failed to check 2FA code"})
+			return
+		}
+		if !valid {
+			h.recordFailure(c, throttleTwoFAPrefix+userResp.Id)
+			recordAuditAs(h.grpcClient, c, "", auditActionLogin, userResp.Id, false)
+			c.JSON(401, gin.H{"error": "Invalid 2FA code"})
+			return
+		}
+	} else {
+		used, err := h.grpcClient.UseRecoveryCode(loginContext(userResp.Id), userResp.Id, hashRecoveryCode(req.RecoveryCode))
+		if err != nil {
+			c.JSON(500, gin.H{"error": "Failed to check recovery code"})
+			return
+		}
+		if !used {
+			h.recordFailure(c, throttleTwoFAPrefix+userResp.Id)
+			recordAuditAs(h.grpcClient, c, "", auditActionLogin, userResp.Id, false)
+			c.JSON(401, gin.H{"error": "Invalid recovery code"})
+			return
+		}
+	}
+	h.recordSuccess(c, throttleTwoFAPrefix+userResp.Id)
+
+	// Аккаунт могли отключить между вводом пароля и второго фактора
+	if !loginAllowed(c, userResp) {
+		return
+	}
+	h.issueTokens(c, userResp)
+}
+
+// consumeChallengeToken гасит challenge-токен до проверки второго фактора: по нему нельзя
+// ни войти повторно, ни проверить ещё один код. При ошибке отвечает клиенту и возвращает false
+func (h *AuthHandler) consumeChallengeToken(c *gin.Context, challengeID string) bool {
+	fresh, err := h.useChallengeToken(c.Request.Context(), challengeID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to check challenge token"})
+		return false
+	}
+	if !fresh {
+		c.JSON(401, gin.H{"error": "Invalid or expired challenge token"})
+		return false
+	}
+	return true
+}
+
+// checkTOTPCode проверяет TOTP-код и запоминает его шаг: принятый код, как и коды
+// более ранних шагов, повторно не принимается
+func (h *AuthHandler) checkTOTPCode(ctx context.Context, userID, secret, code string) (bool, error) {
+	step, ok := totpStep(code, secret, time.Now())
+	if !ok {
+		return false, nil
+	}
+	return h.grpcClient.UseTOTPStep(ctx, userID, step)
+}
+
+// totpStep возвращает номер шага, которому соответствует код, с учётом расхождения часов.
+// Из совпавших шагов выбирается самый поздний
+func totpStep(code, secret string, now time.Time) (int64, bool) {
+	opts := totp.ValidateOpts{
+		Period:    totpPeriod,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	}
+	current := now.Unix() / totpPeriod
+	for step := current + totpSkew; step >= current-totpSkew; step-- {
+		ok, err := totp.ValidateCustom(code, secret, time.Unix(step*totpPeriod, 0), opts)
+		if err == nil && ok {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// replaceRecoveryCodes генерирует новый набор кодов восстановления и сохраняет их хэши
+func (h *AuthHandler) replaceRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = hashRecoveryCode(code)
+	}
+
+	if err := h.grpcClient.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// generateRecoveryCode возвращает случайный код вида "abcde-fghij" (50 бит энтропии)
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.EncodeToString(buf))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// hashRecoveryCode нормализует код (регистр, дефисы, пробелы) и возвращает его SHA-256
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
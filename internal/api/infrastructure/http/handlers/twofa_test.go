@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/cache"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/ratelimit"
+	"github.com/pquerna/otp/totp"
+)
+
+func TestTOTPStep(t *testing.T) {
+	const secret = "JBSWY3DPEHPK3PXP"
+	now := time.Unix(1_700_000_015, 0) // середина шага
+	current := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name     string
+		codeAt   time.Time
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", now, current, true},
+		{"previous step", now.Add(-totpPeriod * time.Second), current - 1, true},
+		{"next step", now.Add(totpPeriod * time.Second), current + 1, true},
+		{"too old", now.Add(-2 * totpPeriod * time.Second), 0, false},
+		{"too new", now.Add(2 * totpPeriod * time.Second), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := totp.GenerateCode(secret, tt.codeAt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			step, ok := totpStep(code, secret, now)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("totpStep = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+
+	if _, ok := totpStep("abcdef", secret, now); ok {
+		t.Error("totpStep accepted a non-numeric code")
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	format := regexp.MustCompile(`^[a-z2-7]{5}-[a-z2-7]{5}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !format.MatchString(code) {
+			t.Fatalf("recovery code %q has unexpected format", code)
+		}
+		if seen[code] {
+			t.Fatalf("recovery code %q generated twice", code)
+		}
+		seen[code] = true
+	}
+
+	want := hashRecoveryCode("abcde-fghij")
+	for _, input := range []string{"abcdefghij", "ABCDE-FGHIJ", " abcde fghij "} {
+		if got := hashRecoveryCode(input); got != want {
+			t.Errorf("hashRecoveryCode(%q) differs from the canonical form", input)
+		}
+	}
+	if hashRecoveryCode("abcde-fghik") == want {
+		t.Error("different recovery codes have the same hash")
+	}
+}
+
+func TestChallengeTokenIsSingleUse(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	h := &AuthHandler{cache: cache.NewMemoryCache()}
+
+	token, err := generateChallengeToken("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	userID, jti, err := parseChallengeToken(token)
+	if err != nil || userID != "user-1" || jti == "" {
+		t.Fatalf("parseChallengeToken = (%q, %q, %v)", userID, jti, err)
+	}
+
+	if fresh, err := h.useChallengeToken(context.Background(), jti); err != nil || !fresh {
+		t.Fatalf("first use = (%v, %v), want fresh", fresh, err)
+	}
+	if fresh, err := h.useChallengeToken(context.Background(), jti); err != nil || fresh {
+		t.Fatalf("second use = (%v, %v), want already used", fresh, err)
+	}
+
+	other, _ := generateChallengeToken("user-1")
+	if _, otherJTI, _ := parseChallengeToken(other); otherJTI == jti {
+		t.Error("challenge tokens share a jti")
+	}
+}
+
+func TestChallengeTokenRejectsOtherTokenTypes(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	h := &AuthHandler{}
+
+	access, err := h.generateAccessToken("user-1", roleUser, "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := parseChallengeToken(access); err == nil {
+		t.Error("parseChallengeToken accepted an access token")
+	}
+}
+
+// twoFAService — БД-сервис с одним пользователем с включённой 2FA, у которого
+// ни один код восстановления не подходит
+type twoFAService struct {
+	auditService
+	userID string
+
+	checksMu           sync.Mutex
+	recoveryCodeChecks int
+}
+
+func (s *twoFAService) GetUserByID(ctx context.Context, req *api.UserIDRequest) (*api.UserResponse, error) {
+	return &api.UserResponse{Id: s.userID, TwofaEnabled: true, Status: userStatusActive}, nil
+}
+
+func (s *twoFAService) UseRecoveryCode(ctx context.Context, req *api.UseRecoveryCodeRequest) (*api.UseRecoveryCodeResponse, error) {
+	s.checksMu.Lock()
+	defer s.checksMu.Unlock()
+	s.recoveryCodeChecks++
+	return &api.UseRecoveryCodeResponse{Used: false}, nil
+}
+
+// Challenge-токен гасится до проверки кода: повторно предъявленный токен
+// не доходит до кодов восстановления
+func TestLogin2FAConsumesChallengeBeforeCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	service := &twoFAService{userID: uuid.New().String()}
+	h := &AuthHandler{
+		grpcClient: newTestClient(t, service),
+		cache:      cache.NewMemoryCache(),
+		throttler:  ratelimit.NewThrottlerFromEnv(ratelimit.NewMemoryStore()),
+	}
+	r := gin.New()
+	r.POST("/login/2fa", h.login2FAHandler)
+
+	challengeToken, err := generateChallengeToken(service.userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := `{"challenge_token":"` + challengeToken + `","recovery_code":"abcde-fghij"}`
+
+	tests := []struct {
+		name       string
+		wantError  string
+		wantChecks int
+	}{
+		{"first attempt", "Invalid recovery code", 1},
+		{"used challenge token", "Invalid or expired challenge token", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login/2fa", strings.NewReader(body)))
+
+			if w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), tt.wantError) {
+				t.Errorf("response = %d %s, want 401 %q", w.Code, w.Body.String(), tt.wantError)
+			}
+			service.checksMu.Lock()
+			defer service.checksMu.Unlock()
+			if service.recoveryCodeChecks != tt.wantChecks {
+				t.Errorf("recovery code checks = %d, want %d", service.recoveryCodeChecks, tt.wantChecks)
+			}
+		})
+	}
+}
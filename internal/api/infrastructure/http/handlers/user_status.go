@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Статусы аккаунта
+const (
+	userStatusActive          = "active"
+	userStatusSuspended       = "suspended"
+	userStatusPendingDeletion = "pending_deletion"
+)
+
+// Префикс ключа кэша со статусом аккаунта
+const userStatusKeyPrefix = "user_status:"
+
+// Сколько кэшируется статус аккаунта: за это время отключение вступает в силу
+// и на других экземплярах API, даже если их кэш не сброшен
+const userStatusCacheTTL = 10 * time.Second
+
+// requireActiveUser пропускает запрос только от активного аккаунта.
+// Иначе отвечает ошибкой и прерывает цепочку обработчиков
+func (h *AuthHandler) requireActiveUser(c *gin.Context, userID string) bool {
+	userStatus, err := h.userStatus(c.Request.Context(), userID)
+	if status.Code(err) == codes.NotFound {
+		c.JSON(401, gin.H{"error": "Invalid token"})
+		c.Abort()
+		return false
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to check account status"})
+		c.Abort()
+		return false
+	}
+	if userStatus != userStatusActive {
+		c.JSON(403, gin.H{"error": accountStatusError(userStatus)})
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+// userStatus возвращает статус аккаунта, по возможности из кэша
+func (h *AuthHandler) userStatus(ctx context.Context, userID string) (string, error) {
+	value, err := h.cache.Get(ctx, userStatusKeyPrefix+userID)
+	if err != nil {
+		return "", err
+	}
+	if value != "" {
+		return value, nil
+	}
+
+	userResp, err := h.grpcClient.GetUserByID(loginContext(userID), userID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := h.cache.Set(ctx, userStatusKeyPrefix+userID, userResp.Status, userStatusCacheTTL); err != nil {
+		log.Printf("Failed to cache status of user %s: %v", userID, err)
+	}
+	return userResp.Status, nil
+}
+
+// forgetUserStatus сбрасывает кэшированный статус, чтобы изменение вступило в силу сразу
+func (h *AuthHandler) forgetUserStatus(ctx context.Context, userID string) {
+	if err := h.cache.Delete(ctx, userStatusKeyPrefix+userID); err != nil {
+		log.Printf("Failed to reset cached status of user %s: %v", userID, err)
+	}
+}
+
+// accountStatusError — сообщение об ошибке для неактивного аккаунта
+func accountStatusError(userStatus string) string {
+	switch userStatus {
+	case userStatusSuspended:
+		return "Account is suspended"
+	case userStatusPendingDeletion:
+		return "Account is scheduled for deletion"
+	default:
+		return "Account is not active"
+	}
+}
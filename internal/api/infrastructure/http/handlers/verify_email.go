@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Назначение токена подтверждения email в email_tokens
+const emailTokenPurposeVerifyEmail = "verify_email"
+
+// Время жизни ссылки для подтверждения email
+const verifyEmailTokenTTL = 24 * time.Hour
+
+// Минимальный интервал между повторными письмами на один адрес (подтверждение email, вход по ссылке)
+const verifyEmailResendInterval = time.Minute
+
+const verifyEmailResendKeyPrefix = "verify_email_resend:"
+
+// Политики EMAIL_VERIFICATION_POLICY: что запрещено до подтверждения email
+const (
+	emailVerificationPolicyNone  = "none"  // ничего
+	emailVerificationPolicyTasks = "tasks" // создание задач
+	emailVerificationPolicyLogin = "login" // вход
+)
+
+// emailVerificationPolicy возвращает политику из окружения (по умолчанию none)
+func emailVerificationPolicy() string {
+	switch policy := strings.ToLower(os.Getenv("EMAIL_VERIFICATION_POLICY")); policy {
+	case emailVerificationPolicyTasks, emailVerificationPolicyLogin:
+		return policy
+	default:
+		return emailVerificationPolicyNone
+	}
+}
+
+// requireVerifiedEmail не пускает к маршруту пользователей с неподтверждённым email,
+// если это требует политика tasks (при политике login такие пользователи не получают токены)
+func (h *AuthHandler) requireVerifiedEmail(c *gin.Context) {
+	if emailVerificationPolicy() != emailVerificationPolicyTasks {
+		c.Next()
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(401, gin.H{"error": "User ID not found in context"})
+		c.Abort()
+		return
+	}
+
+	userResp, err := h.grpcClient.GetUserByID(userContext(c), userID.(string))
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get user"})
+		c.Abort()
+		return
+	}
+	if !userResp.EmailVerified {
+		c.JSON(403, gin.H{"error": "Email verification required"})
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
+
+// verifyEmailHandler подтверждает email по токену из письма
+func (h *AuthHandler) verifyEmailHandler(c *gin.Context) {
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Token == "" {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	userID, err := h.grpcClient.VerifyEmail(context.Background(), hashToken(req.Token))
+	if status.Code(err) == codes.NotFound {
+		c.JSON(400, gin.H{"error": "Invalid or expired verification token"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to verify email"})
+		return
+	}
+	recordAuditAs(h.grpcClient, c, userID, auditActionEmailVerify, userID, true)
+
+	c.JSON(200, gin.H{"message": "Email has been verified"})
+}
+
+// resendVerificationEmailHandler повторно отправляет письмо для подтверждения.
+// Маршрут публичный, чтобы им могли воспользоваться и при политике login; ответ не выдаёт,
+// зарегистрирован ли email. Частота ограничивается через кэш, если он настроен
+func (h *AuthHandler) resendVerificationEmailHandler(c *gin.Context) {
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Email == "" {
+		c.JSON(400, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	if h.emailSentRecently(c, verifyEmailResendKeyPrefix, req.Email, "Verification email was sent recently, try again later") {
+		return
+	}
+
+	go h.resendVerificationEmail(req.Email)
+
+	c.JSON(202, gin.H{"message": "If the email is registered and not verified, a verification link has been sent"})
+}
+
+// emailSentRecently ограничивает частоту писем на один адрес. Отправки учитываются в том же
+// хранилище, что и неудачные попытки входа, поэтому ограничение действует и без Redis.
+// Если письмо уже отправлялось, отвечает 429 с сообщением message и возвращает true
+func (h *AuthHandler) emailSentRecently(c *gin.Context, keyPrefix, email, message string) bool {
+	ctx := c.Request.Context()
+	key := keyPrefix + strings.ToLower(email)
+	sent, err := h.attempts.Attempts(ctx, key, verifyEmailResendInterval)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to check resend limit"})
+		return true
+	}
+	if len(sent) > 0 {
+		wait := time.Until(sent[len(sent)-1].Add(verifyEmailResendInterval))
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		c.JSON(429, gin.H{"error": message})
+		return true
+	}
+	if err := h.attempts.AddAttempt(ctx, key, time.Now(), verifyEmailResendInterval); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to save resend limit"})
+		return true
+	}
+	return false
+}
+
+// resendVerificationEmail отправляет письмо, если пользователь существует и ещё не подтвердил email
+func (h *AuthHandler) resendVerificationEmail(email string) {
+	userResp, err := h.grpcClient.GetUserByEmail(context.Background(), email)
+	if status.Code(err) == codes.NotFound {
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get user for email verification: %v", err)
+		return
+	}
+	if userResp.EmailVerified {
+		return
+	}
+
+	h.sendVerificationEmail(userResp.Id, userResp.Email)
+}
+
+// sendVerificationEmail создаёт токен подтверждения и отправляет ссылку на email
+func (h *AuthHandler) sendVerificationEmail(userID, email string) {
+	ctx := context.Background()
+
+	token, err := randomToken()
+	if err != nil {
+		log.Printf("Failed to generate verification token: %v", err)
+		return
+	}
+	expiresAt := time.Now().Add(verifyEmailTokenTTL)
+	if err := h.grpcClient.CreateEmailToken(loginContext(userID), userID, emailTokenPurposeVerifyEmail, hashToken(token), expiresAt); err != nil {
+		log.Printf("Failed to save verification token: %v", err)
+		return
+	}
+
+	link := frontendURL("/verify-email", token)
+	body := "To confirm your email address, open the link below. It expires in 24 hours.\n\n" +
+		link + "\n\nIf you did not create an account, ignore this email."
+	if err := h.mailer.Send(ctx, email, "Confirm your email", body); err != nil {
+		log.Printf("Failed to send verification email: %v", err)
+	}
+}
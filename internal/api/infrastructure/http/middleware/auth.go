@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Ключ контекста gin, под которым authMiddleware сохраняет Principal
+const PrincipalKey = "principal"
+
+// Principal — кто выполняет запрос и что ему разрешено
+type Principal struct {
+	UserID    string
+	SessionID string   // пусто для персональных токенов
+	Roles     []string // роли пользователя; у персональных токенов ролей нет
+	Scopes    []string // права доступа токена
+	// Администратор, действующий от имени UserID (claim act); пусто без имперсонации
+	ActorID string
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// Impersonated сообщает, что запрос выполняет администратор от имени пользователя
+func (p *Principal) Impersonated() bool {
+	return p.ActorID != ""
+}
+
+// GetPrincipal возвращает Principal запроса или nil, если запрос не аутентифицирован
+func GetPrincipal(c *gin.Context) *Principal {
+	value, ok := c.Get(PrincipalKey)
+	if !ok {
+		return nil
+	}
+	principal, _ := value.(*Principal)
+	return principal
+}
+
+// RequireScope пропускает запрос, только если у токена есть scope
+func RequireScope(scope string) gin.HandlerFunc {
+	return require("scope", scope, func(p *Principal) bool { return p.HasScope(scope) })
+}
+
+// RequireRole пропускает запрос, только если у пользователя есть роль.
+// Персональные токены ролей не несут, поэтому такие маршруты им недоступны
+func RequireRole(role string) gin.HandlerFunc {
+	return require("role", role, func(p *Principal) bool { return p.HasRole(role) })
+}
+
+// RequireSession закрывает маршрут для персональных токенов: управлять аккаунтом
+// (пароль, 2FA, сессии, токены) можно только после входа
+func RequireSession() gin.HandlerFunc {
+	return require("token", "session", func(p *Principal) bool { return p.SessionID != "" })
+}
+
+// DenyImpersonation закрывает маршрут для администратора, действующего от имени пользователя:
+// сменить пароль, 2FA или удалить аккаунт может только сам пользователь
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := GetPrincipal(c)
+		if principal != nil && principal.Impersonated() {
+			c.JSON(403, gin.H{"error": "This action is not allowed while impersonating a user"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// Forbidden отвечает 403 в едином формате для всех проверок прав
+func Forbidden(c *gin.Context, kind, required string) {
+	c.JSON(403, gin.H{
+		"error":    "Insufficient permissions",
+		"required": gin.H{kind: required},
+	})
+	c.Abort()
+}
+
+func require(kind, required string, allowed func(*Principal) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := GetPrincipal(c)
+		if principal == nil {
+			c.JSON(401, gin.H{"error": "Authentication required"})
+			c.Abort()
+			return
+		}
+		if !allowed(principal) {
+			Forbidden(c, kind, required)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationRecorder сохраняет запрос, выполненный администратором от имени пользователя,
+// например в журнал аудита. Вызывается после обработки запроса, статус ответа уже известен
+type ImpersonationRecorder func(c *gin.Context, principal *Principal)
+
+// LogImpersonation пишет в лог каждый запрос, выполненный администратором от имени
+// пользователя: кто, за кого, что и с каким результатом, и передаёт его record.
+// Ставится после аутентификации
+func LogImpersonation(record ImpersonationRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		principal := GetPrincipal(c)
+		if principal == nil || !principal.Impersonated() {
+			return
+		}
+		log.Printf("Impersonated request: actor=%s user=%s %s %s status=%d ip=%s",
+			principal.ActorID, principal.UserID, c.Request.Method, c.Request.URL.Path, c.Writer.Status(), c.ClientIP())
+		if record != nil {
+			record(c, principal)
+		}
+	}
+}
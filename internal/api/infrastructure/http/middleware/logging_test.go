@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLogImpersonationRecordsImpersonatedRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		want      bool
+	}{
+		{"anonymous", nil, false},
+		{"user", &Principal{UserID: "user-1"}, false},
+		{"impersonated", &Principal{UserID: "user-1", ActorID: "admin-1"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recorded *Principal
+			var status int
+			record := func(c *gin.Context, principal *Principal) {
+				recorded = principal
+				status = c.Writer.Status()
+			}
+
+			r := gin.New()
+			r.GET("/profile", func(c *gin.Context) {
+				if tt.principal != nil {
+					c.Set(PrincipalKey, tt.principal)
+				}
+				c.Next()
+			}, LogImpersonation(record), DenyImpersonation(), func(c *gin.Context) {
+				c.Status(204)
+			})
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/profile", nil))
+
+			if (recorded != nil) != tt.want {
+				t.Fatalf("recorded = %v, want %v", recorded != nil, tt.want)
+			}
+			// Отклонённый запрос тоже попадает в журнал вместе со статусом ответа
+			if tt.want && (recorded.ActorID != "admin-1" || status != 403) {
+				t.Errorf("recorded actor %q status %d, want admin-1 and 403", recorded.ActorID, status)
+			}
+		})
+	}
+}
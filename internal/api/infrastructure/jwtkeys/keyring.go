@@ -0,0 +1,237 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Имя файла-манифеста в каталоге ключей
+const manifestFile = "keys.json"
+
+// manifest описывает ключи и расписание ротации:
+//
+//	{"keys": [{"kid": "2026-10", "file": "2026-10.pem", "not_before": "2026-10-01T00:00:00Z"}]}
+//
+// Ключ начинает подписывать токены с not_before и остаётся в JWKS ещё overlap после того,
+// как его сменил следующий. Ключи с not_before в будущем публикуются заранее
+type manifest struct {
+	Keys []struct {
+		ID        string    `json:"kid"`
+		File      string    `json:"file"`
+		NotBefore time.Time `json:"not_before"`
+	} `json:"keys"`
+}
+
+// Key — закрытый ключ подписи с идентификатором kid
+type Key struct {
+	ID        string
+	NotBefore time.Time
+	Method    jwt.SigningMethod
+	private   interface{}
+	public    interface{}
+}
+
+// KeyRing — набор ключей подписи access-токенов (RS256 или EdDSA), загружаемый из каталога
+type KeyRing struct {
+	dir     string
+	overlap time.Duration
+
+	mu   sync.RWMutex
+	keys []*Key // по возрастанию NotBefore
+}
+
+// NewKeyRingFromEnv загружает ключи из JWT_KEYS_DIR. Если переменная не задана,
+// возвращает nil: токены подписываются HS256 с JWT_SECRET. tokenTTL — наибольшее время
+// жизни подписываемых токенов, JWT_KEY_OVERLAP не может быть меньше него
+func NewKeyRingFromEnv(tokenTTL time.Duration) (*KeyRing, error) {
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir == "" {
+		return nil, nil
+	}
+
+	overlap := time.Hour
+	if value := os.Getenv("JWT_KEY_OVERLAP"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid JWT_KEY_OVERLAP: %v", err)
+		}
+		overlap = parsed
+	}
+
+	return LoadKeyRing(dir, overlap, tokenTTL)
+}
+
+// LoadKeyRing читает манифест и ключи из каталога dir. overlap должен быть не меньше
+// времени жизни токенов tokenTTL, иначе токены, подписанные предыдущим ключом, перестанут
+// проверяться до истечения срока действия: такая конфигурация отклоняется
+func LoadKeyRing(dir string, overlap, tokenTTL time.Duration) (*KeyRing, error) {
+	if overlap < tokenTTL {
+		return nil, fmt.Errorf("Key overlap %v is shorter than the token lifetime %v", overlap, tokenTTL)
+	}
+
+	ring := &KeyRing{dir: dir, overlap: overlap}
+	if err := ring.Reload(); err != nil {
+		return nil, err
+	}
+	return ring, nil
+}
+
+// Reload перечитывает каталог ключей. При ошибке остаётся прежний набор
+func (r *KeyRing) Reload() error {
+	data, err := os.ReadFile(filepath.Join(r.dir, manifestFile))
+	if err != nil {
+		return fmt.Errorf("Failed to read key manifest: %v", err)
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("Failed to parse key manifest: %v", err)
+	}
+
+	keys := make([]*Key, 0, len(m.Keys))
+	seen := make(map[string]bool)
+	for _, entry := range m.Keys {
+		if entry.ID == "" || seen[entry.ID] {
+			return fmt.Errorf("Empty or duplicate kid %q in key manifest", entry.ID)
+		}
+		seen[entry.ID] = true
+
+		key, err := loadKey(filepath.Join(r.dir, entry.File))
+		if err != nil {
+			return fmt.Errorf("Failed to load key %q: %v", entry.ID, err)
+		}
+		key.ID = entry.ID
+		key.NotBefore = entry.NotBefore
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("Key manifest has no keys")
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].NotBefore.Before(keys[j].NotBefore) })
+
+	r.mu.Lock()
+	r.keys = keys
+	r.mu.Unlock()
+	return nil
+}
+
+// StartAutoReload периодически перечитывает каталог, чтобы подхватывать новые ключи без перезапуска
+func (r *KeyRing) StartAutoReload(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := r.Reload(); err != nil {
+				log.Printf("Failed to reload JWT keys: %v", err)
+			}
+		}
+	}()
+}
+
+// SigningKey возвращает ключ с последним наступившим not_before
+func (r *KeyRing) SigningKey() (*Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	var active *Key
+	for _, key := range r.keys {
+		if key.NotBefore.After(now) {
+			break
+		}
+		active = key
+	}
+	if active == nil {
+		return nil, fmt.Errorf("No active JWT signing key")
+	}
+	return active, nil
+}
+
+// Sign подписывает claims активным ключом и проставляет kid в заголовок
+func (r *KeyRing) Sign(claims jwt.Claims) (string, error) {
+	key, err := r.SigningKey()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
+}
+
+// Keyfunc находит открытый ключ по kid для jwt.Parse. Алгоритм токена должен совпадать с ключом
+func (r *KeyRing) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	for _, key := range r.verificationKeys() {
+		if key.ID != kid {
+			continue
+		}
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+		}
+		return key.public, nil
+	}
+	return nil, fmt.Errorf("Unknown kid %q", kid)
+}
+
+// verificationKeys возвращает ключи, которыми могут быть подписаны действующие токены,
+// и будущие ключи: ключ выводится из оборота через overlap после активации следующего
+func (r *KeyRing) verificationKeys() []*Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	keys := make([]*Key, 0, len(r.keys))
+	for i, key := range r.keys {
+		if i+1 < len(r.keys) && r.keys[i+1].NotBefore.Add(r.overlap).Before(now) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// loadKey читает закрытый ключ PKCS#8 (или PKCS#1 для RSA) в формате PEM
+func loadKey(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("No PEM block found")
+	}
+
+	var private interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch private := private.(type) {
+	case *rsa.PrivateKey:
+		if private.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("RSA key must be at least 2048 bits")
+		}
+		return &Key{Method: jwt.SigningMethodRS256, private: private, public: &private.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &Key{Method: jwt.SigningMethodEdDSA, private: private, public: private.Public()}, nil
+	default:
+		return nil, fmt.Errorf("Unsupported key type %T, expected RSA or Ed25519", private)
+	}
+}
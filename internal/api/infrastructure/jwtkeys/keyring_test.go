@@ -0,0 +1,225 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type testKey struct {
+	kid       string
+	notBefore time.Time
+	pem       []byte
+}
+
+func ed25519PEM(t *testing.T) []byte {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func rsaPEM(t *testing.T, bits int) []byte {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)})
+}
+
+// writeKeys записывает ключи и манифест в каталог dir
+func writeKeys(t *testing.T, dir string, keys ...testKey) {
+	t.Helper()
+	var m manifest
+	for _, key := range keys {
+		file := key.kid + ".pem"
+		if err := os.WriteFile(filepath.Join(dir, file), key.pem, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		m.Keys = append(m.Keys, struct {
+			ID        string    `json:"kid"`
+			File      string    `json:"file"`
+			NotBefore time.Time `json:"not_before"`
+		}{key.kid, file, key.notBefore})
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifestFile), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// signWith подписывает токен конкретным ключом набора, минуя выбор активного ключа
+func signWith(t *testing.T, ring *KeyRing, kid string) string {
+	t.Helper()
+	for _, key := range ring.keys {
+		if key.ID == kid {
+			token := jwt.NewWithClaims(key.Method, jwt.MapClaims{"sub": "user"})
+			token.Header["kid"] = kid
+			signed, err := token.SignedString(key.private)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return signed
+		}
+	}
+	t.Fatalf("no key %q", kid)
+	return ""
+}
+
+func TestKeyRingRotation(t *testing.T) {
+	now := time.Now()
+	dir := t.TempDir()
+	writeKeys(t, dir,
+		testKey{"retired", now.Add(-72 * time.Hour), ed25519PEM(t)},
+		testKey{"previous", now.Add(-48 * time.Hour), rsaPEM(t, 2048)},
+		testKey{"current", now.Add(-30 * time.Minute), ed25519PEM(t)},
+		testKey{"next", now.Add(24 * time.Hour), ed25519PEM(t)},
+	)
+	ring, err := LoadKeyRing(dir, time.Hour, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("LoadKeyRing: %v", err)
+	}
+
+	active, err := ring.SigningKey()
+	if err != nil || active.ID != "current" {
+		t.Fatalf("SigningKey = %v, %v; want current", active, err)
+	}
+
+	signed, err := ring.Sign(jwt.MapClaims{"sub": "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Parse(signed, ring.Keyfunc)
+	if err != nil || !token.Valid || token.Header["kid"] != "current" {
+		t.Fatalf("Parse(signed) = %v, %v", token, err)
+	}
+
+	tests := []struct {
+		kid   string
+		valid bool
+	}{
+		{"previous", true}, // следующий ключ активен меньше overlap
+		{"next", true},     // будущий ключ публикуется заранее
+		{"retired", false}, // следующий ключ активен дольше overlap
+	}
+	for _, tt := range tests {
+		_, err := jwt.Parse(signWith(t, ring, tt.kid), ring.Keyfunc)
+		if (err == nil) != tt.valid {
+			t.Errorf("token signed with %q: err = %v, want valid %v", tt.kid, err, tt.valid)
+		}
+	}
+
+	var kids []string
+	for _, jwk := range ring.JWKS().Keys {
+		kids = append(kids, jwk.KeyID)
+	}
+	if len(kids) != 3 || kids[0] != "previous" || kids[1] != "current" || kids[2] != "next" {
+		t.Errorf("JWKS kids = %v, want [previous current next]", kids)
+	}
+}
+
+func TestKeyRingKeyfuncRejects(t *testing.T) {
+	dir := t.TempDir()
+	writeKeys(t, dir, testKey{"current", time.Now().Add(-time.Minute), ed25519PEM(t)})
+	ring, err := LoadKeyRing(dir, time.Hour, 15*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unknown := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{"sub": "user"})
+	unknown.Header["kid"] = "unknown"
+	_, other, _ := ed25519.GenerateKey(rand.Reader)
+	unknownSigned, _ := unknown.SignedString(other)
+
+	// HS256 с открытым ключом в качестве секрета: подмена алгоритма
+	public := ring.keys[0].public.(ed25519.PublicKey)
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"})
+	confused.Header["kid"] = "current"
+	confusedSigned, _ := confused.SignedString([]byte(public))
+
+	noKid := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{"sub": "user"})
+	noKidSigned, _ := noKid.SignedString(ring.keys[0].private)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"unknown kid", unknownSigned},
+		{"wrong alg", confusedSigned},
+		{"missing kid", noKidSigned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := jwt.Parse(tt.token, ring.Keyfunc); err == nil {
+				t.Error("token accepted")
+			}
+		})
+	}
+}
+
+func TestKeyRingReloadKeepsKeysOnError(t *testing.T) {
+	dir := t.TempDir()
+	writeKeys(t, dir, testKey{"current", time.Now().Add(-time.Minute), ed25519PEM(t)})
+	ring, err := LoadKeyRing(dir, time.Hour, 15*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := ed25519PEM(t)
+	writeKeys(t, dir, testKey{"a", time.Now(), key}, testKey{"a", time.Now(), key})
+	if err := ring.Reload(); err == nil {
+		t.Fatal("Reload accepted a duplicate kid")
+	}
+	if active, err := ring.SigningKey(); err != nil || active.ID != "current" {
+		t.Fatalf("SigningKey after failed reload = %v, %v", active, err)
+	}
+}
+
+func TestLoadKeyRingRejectsWeakRSA(t *testing.T) {
+	dir := t.TempDir()
+	writeKeys(t, dir, testKey{"weak", time.Now(), rsaPEM(t, 1024)})
+	if _, err := LoadKeyRing(dir, time.Hour, 15*time.Minute); err == nil {
+		t.Fatal("LoadKeyRing accepted a 1024-bit RSA key")
+	}
+}
+
+// Перекрытие короче времени жизни токенов отклоняется при загрузке, а не после ротации
+func TestLoadKeyRingRejectsShortOverlap(t *testing.T) {
+	dir := t.TempDir()
+	writeKeys(t, dir, testKey{"current", time.Now(), ed25519PEM(t)})
+	if _, err := LoadKeyRing(dir, 10*time.Minute, 15*time.Minute); err == nil {
+		t.Fatal("LoadKeyRing accepted an overlap shorter than the token lifetime")
+	}
+	if _, err := LoadKeyRing(dir, 15*time.Minute, 15*time.Minute); err != nil {
+		t.Fatalf("LoadKeyRing with overlap equal to the token lifetime: %v", err)
+	}
+}
+
+func TestNewKeyRingFromEnvRejectsShortOverlap(t *testing.T) {
+	dir := t.TempDir()
+	writeKeys(t, dir, testKey{"current", time.Now(), ed25519PEM(t)})
+	t.Setenv("JWT_KEYS_DIR", dir)
+	t.Setenv("JWT_KEY_OVERLAP", "5m")
+	if _, err := NewKeyRingFromEnv(15 * time.Minute); err == nil {
+		t.Fatal("NewKeyRingFromEnv accepted JWT_KEY_OVERLAP shorter than the token lifetime")
+	}
+}
@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogMailer для локальной разработки: пишет письма в файл или в лог вместо отправки
+type LogMailer struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLogMailer создаёт LogMailer. Если path пуст, письма выводятся в лог
+func NewLogMailer(path string) *LogMailer {
+	return &LogMailer{path: path}
+}
+
+func (m *LogMailer) Send(ctx context.Context, to, subject, body string) error {
+	msg := fmt.Sprintf("=== %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC3339), to, subject, body)
+
+	if m.path == "" {
+		log.Print(msg)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to open mail log: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(msg); err != nil {
+		return fmt.Errorf("Failed to write mail log: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/oziev02/checklist-microservices/internal/api/ports"
+)
+
+// NewFromEnv создаёт почтовый клиент по переменной MAILER: "smtp" или "log" (по умолчанию)
+func NewFromEnv() (ports.Mailer, error) {
+	switch os.Getenv("MAILER") {
+	case "smtp":
+		return NewSMTPMailer(
+			os.Getenv("SMTP_HOST"),
+			os.Getenv("SMTP_PORT"),
+			os.Getenv("SMTP_USERNAME"),
+			os.Getenv("SMTP_PASSWORD"),
+			os.Getenv("SMTP_FROM"),
+		)
+	case "", "log":
+		return NewLogMailer(os.Getenv("MAIL_LOG_FILE")), nil
+	default:
+		return nil, fmt.Errorf("Unknown MAILER %q", os.Getenv("MAILER"))
+	}
+}
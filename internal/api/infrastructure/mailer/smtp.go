@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPMailer отправляет письма через SMTP-сервер (STARTTLS, если сервер его поддерживает)
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPMailer(host, port, username, password, from string) (*SMTPMailer, error) {
+	if host == "" || port == "" || from == "" {
+		return nil, fmt.Errorf("SMTP_HOST, SMTP_PORT and SMTP_FROM must be set in .env")
+	}
+
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPMailer{
+		addr: host + ":" + port,
+		auth: auth,
+		from: from,
+	}, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, to, subject, body string) error {
+	// Защита от внедрения заголовков через адрес получателя
+	if strings.ContainsAny(to, "\r\n") {
+		return fmt.Errorf("Invalid recipient address")
+	}
+
+	msg := "From: " + m.from + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" + body
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("Failed to send email: %v", err)
+	}
+	return nil
+}
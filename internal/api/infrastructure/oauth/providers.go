@@ -0,0 +1,178 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// UserInfo — учётная запись пользователя у провайдера
+type UserInfo struct {
+	Subject       string // Неизменяемый идентификатор пользователя у провайдера
+	Email         string
+	EmailVerified bool
+}
+
+// Provider — OAuth2-провайдер (authorization code + PKCE)
+type Provider struct {
+	Name   string
+	Config *oauth2.Config
+
+	fetchUserInfo func(ctx context.Context, client *http.Client) (*UserInfo, error)
+}
+
+// Таймаут запросов к провайдеру
+const requestTimeout = 10 * time.Second
+
+// NewProvidersFromEnv создаёт провайдеров, для которых заданы client ID и secret.
+// Адреса эндпоинтов можно переопределить (например, для локального тестового OAuth-сервера)
+func NewProvidersFromEnv() map[string]*Provider {
+	redirectBase := strings.TrimSuffix(getenv("OAUTH_REDIRECT_BASE_URL", "http://localhost:8080"), "/")
+	providers := make(map[string]*Provider)
+
+	if id, secret := os.Getenv("GOOGLE_CLIENT_ID"), os.Getenv("GOOGLE_CLIENT_SECRET"); id != "" && secret != "" {
+		userInfoURL := getenv("GOOGLE_USERINFO_URL", "https://openidconnect.googleapis.com/v1/userinfo")
+		providers["google"] = &Provider{
+			Name: "google",
+			Config: &oauth2.Config{
+				ClientID:     id,
+				ClientSecret: secret,
+				Endpoint: oauth2.Endpoint{
+					AuthURL:  getenv("GOOGLE_AUTH_URL", "https://accounts.google.com/o/oauth2/auth"),
+					TokenURL: getenv("GOOGLE_TOKEN_URL", "https://oauth2.googleapis.com/token"),
+				},
+				RedirectURL: redirectBase + "/oauth/google/callback",
+				Scopes:      []string{"openid", "email"},
+			},
+			fetchUserInfo: func(ctx context.Context, client *http.Client) (*UserInfo, error) {
+				return fetchGoogleUserInfo(ctx, client, userInfoURL)
+			},
+		}
+	}
+
+	if id, secret := os.Getenv("GITHUB_CLIENT_ID"), os.Getenv("GITHUB_CLIENT_SECRET"); id != "" && secret != "" {
+		apiURL := strings.TrimSuffix(getenv("GITHUB_API_URL", "https://api.github.com"), "/")
+		providers["github"] = &Provider{
+			Name: "github",
+			Config: &oauth2.Config{
+				ClientID:     id,
+				ClientSecret: secret,
+				Endpoint: oauth2.Endpoint{
+					AuthURL:  getenv("GITHUB_AUTH_URL", "https://github.com/login/oauth/authorize"),
+					TokenURL: getenv("GITHUB_TOKEN_URL", "https://github.com/login/oauth/access_token"),
+				},
+				RedirectURL: redirectBase + "/oauth/github/callback",
+				Scopes:      []string{"read:user", "user:email"},
+			},
+			fetchUserInfo: func(ctx context.Context, client *http.Client) (*UserInfo, error) {
+				return fetchGitHubUserInfo(ctx, client, apiURL)
+			},
+		}
+	}
+
+	return providers
+}
+
+// AuthCodeURL возвращает адрес страницы входа провайдера с PKCE-challenge для verifier
+func (p *Provider) AuthCodeURL(state, verifier string) string {
+	return p.Config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+}
+
+// Exchange обменивает код авторизации на токен провайдера и получает данные пользователя
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (*UserInfo, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Timeout: requestTimeout})
+
+	token, err := p.Config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to exchange code: %v", err)
+	}
+
+	client := p.Config.Client(ctx, token)
+	client.Timeout = requestTimeout
+
+	info, err := p.fetchUserInfo(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get %s user info: %v", p.Name, err)
+	}
+	if info.Subject == "" {
+		return nil, fmt.Errorf("Empty %s user id", p.Name)
+	}
+	return info, nil
+}
+
+func fetchGoogleUserInfo(ctx context.Context, client *http.Client, userInfoURL string) (*UserInfo, error) {
+	var resp struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+	if err := getJSON(ctx, client, userInfoURL, &resp); err != nil {
+		return nil, err
+	}
+	return &UserInfo{Subject: resp.Sub, Email: resp.Email, EmailVerified: resp.EmailVerified}, nil
+}
+
+func fetchGitHubUserInfo(ctx context.Context, client *http.Client, apiURL string) (*UserInfo, error) {
+	var user struct {
+		ID int64 `json:"id"`
+	}
+	if err := getJSON(ctx, client, apiURL+"/user", &user); err != nil {
+		return nil, err
+	}
+	info := &UserInfo{Subject: strconv.FormatInt(user.ID, 10)}
+	if user.ID == 0 {
+		info.Subject = ""
+	}
+
+	// Публичный email в /user может отсутствовать, берём основной из /user/emails
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getJSON(ctx, client, apiURL+"/user/emails", &emails); err != nil {
+		return nil, err
+	}
+	for _, e := range emails {
+		if e.Primary {
+			info.Email = e.Email
+			info.EmailVerified = e.Verified
+			break
+		}
+	}
+
+	return info, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Unexpected status %d from %s", resp.StatusCode, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,198 @@
+package passkey
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+// Service проводит церемонии WebAuthn (регистрация и вход по passkey).
+// Ответы браузера принимаются как JSON, а состояние между началом и завершением
+// церемонии — как SessionData, поэтому сервис не зависит от HTTP и хранилища
+type Service struct {
+	webAuthn *webauthn.WebAuthn
+}
+
+// NewFromEnv настраивает Relying Party из WEBAUTHN_* переменных окружения.
+// По умолчанию RP ID и origin берутся из адреса веб-приложения: FRONTEND_BASE_URL,
+// а без него APP_BASE_URL
+func NewFromEnv() (*Service, error) {
+	appURL := os.Getenv("FRONTEND_BASE_URL")
+	if appURL == "" {
+		appURL = os.Getenv("APP_BASE_URL")
+	}
+	if appURL == "" {
+		appURL = "http://localhost:8080"
+	}
+
+	rpID := os.Getenv("WEBAUTHN_RP_ID")
+	if rpID == "" {
+		host := strings.TrimPrefix(strings.TrimPrefix(appURL, "https://"), "http://")
+		host, _, _ = strings.Cut(host, "/")
+		host, _, _ = strings.Cut(host, ":")
+		rpID = host
+	}
+
+	origins := []string{strings.TrimSuffix(appURL, "/")}
+	if value := os.Getenv("WEBAUTHN_RP_ORIGINS"); value != "" {
+		origins = strings.Split(value, ",")
+	}
+
+	displayName := os.Getenv("WEBAUTHN_RP_DISPLAY_NAME")
+	if displayName == "" {
+		displayName = "Checklist"
+	}
+
+	return New(rpID, displayName, origins)
+}
+
+func New(rpID, displayName string, origins []string) (*Service, error) {
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: displayName,
+		RPOrigins:     origins,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Service{webAuthn: webAuthn}, nil
+}
+
+// User — пользователь с его passkey. User handle — 16 байт UUID пользователя
+type User struct {
+	ID          uuid.UUID
+	Email       string
+	Credentials []webauthn.Credential
+}
+
+func (u *User) WebAuthnID() []byte {
+	return u.ID[:]
+}
+
+func (u *User) WebAuthnName() string {
+	return u.Email
+}
+
+func (u *User) WebAuthnDisplayName() string {
+	return u.Email
+}
+
+func (u *User) WebAuthnCredentials() []webauthn.Credential {
+	return u.Credentials
+}
+
+func (u *User) WebAuthnIcon() string {
+	return ""
+}
+
+// BeginRegistration создаёт параметры для navigator.credentials.create().
+// Уже зарегистрированные ключи исключаются, ключ создаётся как discoverable (passkey)
+func (s *Service) BeginRegistration(user *User) (*protocol.CredentialCreation, *webauthn.SessionData, error) {
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(user.Credentials))
+	for _, credential := range user.Credentials {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+
+	return s.webAuthn.BeginRegistration(user,
+		webauthn.WithExclusions(exclusions),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		}),
+	)
+}
+
+// FinishRegistration проверяет ответ navigator.credentials.create() (JSON PublicKeyCredential)
+func (s *Service) FinishRegistration(user *User, session webauthn.SessionData, response []byte) (*webauthn.Credential, error) {
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, err
+	}
+	return s.webAuthn.CreateCredential(user, session, parsed)
+}
+
+// BeginLogin создаёт параметры для navigator.credentials.get() с ключами пользователя
+// (passkey как второй фактор после пароля)
+func (s *Service) BeginLogin(user *User) (*protocol.CredentialAssertion, *webauthn.SessionData, error) {
+	return s.webAuthn.BeginLogin(user)
+}
+
+// FinishLogin проверяет ответ navigator.credentials.get() для известного пользователя
+func (s *Service) FinishLogin(user *User, session webauthn.SessionData, response []byte) (*webauthn.Credential, error) {
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, err
+	}
+	return s.webAuthn.ValidateLogin(user, session, parsed)
+}
+
+// BeginDiscoverableLogin создаёт параметры входа без пароля и email: пользователя
+// определяет выбранный в браузере passkey. Требуется проверка пользователя (PIN, биометрия)
+func (s *Service) BeginDiscoverableLogin() (*protocol.CredentialAssertion, *webauthn.SessionData, error) {
+	return s.webAuthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+}
+
+// FinishDiscoverableLogin проверяет ответ входа без пароля. lookup находит пользователя
+// по ID ключа и user handle из ответа
+func (s *Service) FinishDiscoverableLogin(session webauthn.SessionData, response []byte, lookup func(credentialID, userHandle []byte) (*User, error)) (*User, *webauthn.Credential, error) {
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var user *User
+	credential, err := s.webAuthn.ValidateDiscoverableLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+		found, err := lookup(rawID, userHandle)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(found.WebAuthnID(), userHandle) {
+			return nil, fmt.Errorf("User handle does not match credential owner")
+		}
+		user = found
+		return found, nil
+	}, session, parsed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, credential, nil
+}
+
+// Максимальная длина ID ключа в байтах (WebAuthn Level 2, раздел 5.8.3)
+const maxCredentialIDLength = 1023
+
+// CredentialID кодирует ID ключа для хранения и URL (base64url без паддинга)
+func CredentialID(id []byte) string {
+	return base64.RawURLEncoding.EncodeToString(id)
+}
+
+// ParseCredentialID декодирует ID ключа, закодированный CredentialID
+func ParseCredentialID(id string) ([]byte, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid credential id: %v", err)
+	}
+	if len(raw) == 0 || len(raw) > maxCredentialIDLength {
+		return nil, fmt.Errorf("Invalid credential id length %d", len(raw))
+	}
+	return raw, nil
+}
+
+// MarshalCredential сериализует ключ для хранения в БД-сервисе
+func MarshalCredential(credential *webauthn.Credential) ([]byte, error) {
+	return json.Marshal(credential)
+}
+
+func UnmarshalCredential(data []byte) (webauthn.Credential, error) {
+	var credential webauthn.Credential
+	err := json.Unmarshal(data, &credential)
+	return credential, err
+}
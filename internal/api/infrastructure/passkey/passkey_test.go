@@ -0,0 +1,233 @@
+package passkey
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/passkey/softauthn"
+)
+
+const testOrigin = "https://checklist.example"
+
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	service, err := New("checklist.example", "Checklist", []string{testOrigin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service
+}
+
+// register регистрирует новый ключ программного аутентификатора и добавляет его пользователю
+func register(t *testing.T, service *Service, user *User, origin string) (*softauthn.Authenticator, error) {
+	t.Helper()
+	authenticator, err := softauthn.New(origin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	creation, session, err := service.BeginRegistration(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := authenticator.Register(creation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credential, err := service.FinishRegistration(user, *session, response)
+	if err != nil {
+		return authenticator, err
+	}
+	user.Credentials = append(user.Credentials, *credential)
+	return authenticator, nil
+}
+
+// login проводит вход passkey как второй фактор и сохраняет новый счётчик подписей
+func login(t *testing.T, service *Service, user *User, authenticator *softauthn.Authenticator) (*webauthn.Credential, error) {
+	t.Helper()
+	assertion, session, err := service.BeginLogin(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := authenticator.Assert(assertion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credential, err := service.FinishLogin(user, *session, response)
+	if err != nil {
+		return nil, err
+	}
+	for i := range user.Credentials {
+		if bytes.Equal(user.Credentials[i].ID, credential.ID) {
+			user.Credentials[i] = *credential
+		}
+	}
+	return credential, nil
+}
+
+func TestRegistrationAndTwoFactorLogin(t *testing.T) {
+	service := newTestService(t)
+	user := &User{ID: uuid.New(), Email: "ann@example.com"}
+
+	authenticator, err := register(t, service, user, testOrigin)
+	if err != nil {
+		t.Fatalf("registration: %v", err)
+	}
+	if !bytes.Equal(user.Credentials[0].ID, authenticator.CredentialID) {
+		t.Fatal("registered credential id does not match the authenticator")
+	}
+	if !bytes.Equal(authenticator.UserHandle, user.WebAuthnID()) {
+		t.Fatal("user handle is not the user id")
+	}
+
+	for i := 0; i < 2; i++ {
+		credential, err := login(t, service, user, authenticator)
+		if err != nil {
+			t.Fatalf("login %d: %v", i+1, err)
+		}
+		if credential.Authenticator.CloneWarning {
+			t.Fatalf("login %d: unexpected clone warning", i+1)
+		}
+	}
+
+	// Тот же ключ нельзя зарегистрировать повторно: он в списке исключений
+	creation, _, err := service.BeginRegistration(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if excluded := creation.Response.CredentialExcludeList; len(excluded) != 1 || !bytes.Equal(excluded[0].CredentialID, authenticator.CredentialID) {
+		t.Errorf("exclude list = %v, want the registered credential", excluded)
+	}
+}
+
+func TestDiscoverableLogin(t *testing.T) {
+	service := newTestService(t)
+	owner := &User{ID: uuid.New(), Email: "ann@example.com"}
+	authenticator, err := register(t, service, owner, testOrigin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := map[string]*User{CredentialID(authenticator.CredentialID): owner}
+
+	lookup := func(credentialID, userHandle []byte) (*User, error) {
+		user, ok := users[CredentialID(credentialID)]
+		if !ok {
+			return nil, fmt.Errorf("unknown credential")
+		}
+		return user, nil
+	}
+
+	assertion, session, err := service.BeginDiscoverableLogin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assertion.Response.AllowedCredentials) != 0 {
+		t.Error("discoverable login lists allowed credentials")
+	}
+	response, err := authenticator.Assert(assertion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, credential, err := service.FinishDiscoverableLogin(*session, response, lookup)
+	if err != nil {
+		t.Fatalf("FinishDiscoverableLogin: %v", err)
+	}
+	if user != owner || !bytes.Equal(credential.ID, authenticator.CredentialID) {
+		t.Errorf("logged in as %v with %x, want the owner", user.ID, credential.ID)
+	}
+
+	// Ключ, найденный по ID, должен принадлежать пользователю из user handle
+	stranger := &User{ID: uuid.New(), Email: "eve@example.com", Credentials: owner.Credentials}
+	users[CredentialID(authenticator.CredentialID)] = stranger
+	assertion, session, _ = service.BeginDiscoverableLogin()
+	response, _ = authenticator.Assert(assertion)
+	if _, _, err := service.FinishDiscoverableLogin(*session, response, lookup); err == nil {
+		t.Error("login succeeded with a user handle of another user")
+	}
+}
+
+func TestRejectsWrongOrigin(t *testing.T) {
+	service := newTestService(t)
+	user := &User{ID: uuid.New(), Email: "ann@example.com"}
+
+	if _, err := register(t, service, user, "https://evil.example"); err == nil {
+		t.Fatal("registration from another origin succeeded")
+	}
+
+	authenticator, err := register(t, service, user, testOrigin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator.Origin = "https://evil.example"
+	if _, err := login(t, service, user, authenticator); err == nil {
+		t.Fatal("login from another origin succeeded")
+	}
+}
+
+func TestRejectsResponseForAnotherChallenge(t *testing.T) {
+	service := newTestService(t)
+	user := &User{ID: uuid.New(), Email: "ann@example.com"}
+	authenticator, err := register(t, service, user, testOrigin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertion, _, _ := service.BeginLogin(user)
+	response, err := authenticator.Assert(assertion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherSession, _ := service.BeginLogin(user)
+	if _, err := service.FinishLogin(user, *otherSession, response); err == nil {
+		t.Fatal("response signed for another challenge was accepted")
+	}
+}
+
+func TestSignCountRegressionRaisesCloneWarning(t *testing.T) {
+	service := newTestService(t)
+	user := &User{ID: uuid.New(), Email: "ann@example.com"}
+	authenticator, err := register(t, service, user, testOrigin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authenticator.SignCount = 10
+	if credential, err := login(t, service, user, authenticator); err != nil || credential.Authenticator.CloneWarning {
+		t.Fatalf("login = %v, %v; want success", credential, err)
+	}
+
+	// Копия ключа со старым счётчиком
+	authenticator.SignCount = 3
+	credential, err := login(t, service, user, authenticator)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if !credential.Authenticator.CloneWarning {
+		t.Fatal("sign count regression did not raise a clone warning")
+	}
+}
+
+func TestCredentialRoundTrip(t *testing.T) {
+	service := newTestService(t)
+	user := &User{ID: uuid.New(), Email: "ann@example.com"}
+	authenticator, err := register(t, service, user, testOrigin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := MarshalCredential(&user.Credentials[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := UnmarshalCredential(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Credentials = []webauthn.Credential{restored}
+	if _, err := login(t, service, user, authenticator); err != nil {
+		t.Fatalf("login with a restored credential: %v", err)
+	}
+}
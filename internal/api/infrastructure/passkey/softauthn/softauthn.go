@@ -0,0 +1,157 @@
+// Package softauthn — программный аутентификатор WebAuthn для тестов и локальной отладки.
+// Создаёт passkey ES256 с аттестацией "none" и подписывает вход, как это делает браузер
+// вместе с платформенным аутентификатором
+package softauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+// Authenticator хранит один ключ. Нулевое значение не готово к работе, используйте New
+type Authenticator struct {
+	Origin       string // origin, от имени которого работает "браузер"
+	CredentialID []byte
+	UserHandle   []byte
+	SignCount    uint32
+
+	key *ecdsa.PrivateKey
+}
+
+func New(origin string) (*Authenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	credentialID := make([]byte, 32)
+	if _, err := rand.Read(credentialID); err != nil {
+		return nil, err
+	}
+	return &Authenticator{Origin: origin, CredentialID: credentialID, key: key}, nil
+}
+
+// Register отвечает на параметры navigator.credentials.create() и возвращает JSON PublicKeyCredential
+func (a *Authenticator) Register(creation *protocol.CredentialCreation) ([]byte, error) {
+	options := creation.Response
+	switch id := options.User.ID.(type) {
+	case protocol.URLEncodedBase64:
+		a.UserHandle = id
+	case []byte:
+		a.UserHandle = id
+	case string:
+		// Параметры, полученные как JSON ответа API: id закодирован base64url
+		handle, err := base64.RawURLEncoding.DecodeString(id)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid user id: %v", err)
+		}
+		a.UserHandle = handle
+	default:
+		return nil, fmt.Errorf("Unsupported user id type %T", options.User.ID)
+	}
+
+	clientData, err := a.clientData(protocol.CreateCeremony, options.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  1, // P-256
+		XCoord: a.key.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// attestedCredentialData: AAGUID (нули), длина ID, ID, открытый ключ COSE
+	attested := make([]byte, 16, 16+2+len(a.CredentialID)+len(publicKey))
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.CredentialID)))
+	attested = append(attested, a.CredentialID...)
+	attested = append(attested, publicKey...)
+
+	authData := a.authData(options.RelyingParty.ID, protocol.FlagAttestedCredentialData, attested)
+	attestationObject, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": authData,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"id":    base64.RawURLEncoding.EncodeToString(a.CredentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.CredentialID),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+			"attestationObject": base64.RawURLEncoding.EncodeToString(attestationObject),
+		},
+	})
+}
+
+// Assert отвечает на параметры navigator.credentials.get() и возвращает JSON PublicKeyCredential
+func (a *Authenticator) Assert(assertion *protocol.CredentialAssertion) ([]byte, error) {
+	options := assertion.Response
+	if a.UserHandle == nil {
+		return nil, fmt.Errorf("Authenticator is not registered")
+	}
+
+	clientData, err := a.clientData(protocol.AssertCeremony, options.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	a.SignCount++
+	authData := a.authData(options.RelyingPartyID, 0, nil)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"id":    base64.RawURLEncoding.EncodeToString(a.CredentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.CredentialID),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+			"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+			"signature":         base64.RawURLEncoding.EncodeToString(signature),
+			"userHandle":        base64.RawURLEncoding.EncodeToString(a.UserHandle),
+		},
+	})
+}
+
+func (a *Authenticator) clientData(ceremony protocol.CeremonyType, challenge protocol.URLEncodedBase64) ([]byte, error) {
+	return json.Marshal(protocol.CollectedClientData{
+		Type:      ceremony,
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+		Origin:    a.Origin,
+	})
+}
+
+// authData собирает данные аутентификатора: хэш RP ID, флаги (присутствие и проверка
+// пользователя всегда установлены), счётчик подписей и дополнительные данные
+func (a *Authenticator) authData(rpID string, flags protocol.AuthenticatorFlags, extra []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, byte(flags|protocol.FlagUserPresent|protocol.FlagUserVerified))
+	data = binary.BigEndian.AppendUint32(data, a.SignCount)
+	return append(data, extra...)
+}
@@ -0,0 +1,106 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2idParams — параметры Argon2id, записываемые в хэш
+type Argon2idParams struct {
+	Memory      uint32 // КиБ
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// Параметры по умолчанию: 64 МиБ, 3 прохода, 2 потока (RFC 9106, раздел 4)
+var DefaultArgon2idParams = Argon2idParams{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// Допустимые параметры хэша. Повреждённый или подменённый хэш не должен занимать гигабайты
+// памяти или обрушить процесс (argon2.IDKey паникует при t=0 или p=0), а пустой ключ совпал бы
+// с любым паролем
+const (
+	maxArgon2idMemory     = 1024 * 1024 // КиБ, 1 ГиБ
+	maxArgon2idIterations = 100
+	minArgon2idSaltLength = 8
+	minArgon2idKeyLength  = 16
+	maxArgon2idKeyLength  = 1024
+)
+
+// validate проверяет, что с параметрами можно безопасно вычислить хэш
+func (p Argon2idParams) validate() error {
+	switch {
+	case p.Memory > maxArgon2idMemory:
+		return fmt.Errorf("argon2id memory %d KiB exceeds %d KiB", p.Memory, maxArgon2idMemory)
+	case p.Iterations < 1 || p.Iterations > maxArgon2idIterations:
+		return fmt.Errorf("argon2id iterations must be between 1 and %d", maxArgon2idIterations)
+	case p.Parallelism < 1:
+		return fmt.Errorf("argon2id parallelism must be at least 1")
+	case p.SaltLength < minArgon2idSaltLength:
+		return fmt.Errorf("argon2id salt must be at least %d bytes", minArgon2idSaltLength)
+	case p.KeyLength < minArgon2idKeyLength || p.KeyLength > maxArgon2idKeyLength:
+		return fmt.Errorf("argon2id key length must be between %d and %d bytes", minArgon2idKeyLength, maxArgon2idKeyLength)
+	}
+	return nil
+}
+
+// hashArgon2id возвращает хэш в формате PHC: $argon2id$v=19$m=65536,t=3,p=2$<соль>$<хэш>
+func hashArgon2id(password string, params Argon2idParams) (string, error) {
+	salt := make([]byte, params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// verifyArgon2id сравнивает пароль с PHC-хэшем и возвращает параметры, с которыми он создан
+func verifyArgon2id(password, hash string) (bool, Argon2idParams, error) {
+	var params Argon2idParams
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", соль, хэш
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return false, params, fmt.Errorf("Invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, params, fmt.Errorf("Unsupported argon2id version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return false, params, fmt.Errorf("Invalid argon2id parameters")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, params, fmt.Errorf("Invalid argon2id salt")
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, params, fmt.Errorf("Invalid argon2id hash")
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	if err := params.validate(); err != nil {
+		return false, params, fmt.Errorf("Invalid argon2id parameters: %v", err)
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	return subtle.ConstantTimeCompare(key, other) == 1, params, nil
+}
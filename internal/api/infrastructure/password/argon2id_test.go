@@ -0,0 +1,78 @@
+package password
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+// Параметры с минимальной стоимостью, чтобы тесты шли быстро
+var testArgon2idParams = Argon2idParams{
+	Memory:      64,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestArgon2idRoundTrip(t *testing.T) {
+	hash, err := hashArgon2id("correct horse", testArgon2idParams)
+	if err != nil {
+		t.Fatalf("hashArgon2id: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Fatalf("unexpected PHC string %q", hash)
+	}
+
+	ok, params, err := verifyArgon2id("correct horse", hash)
+	if err != nil || !ok {
+		t.Fatalf("verifyArgon2id(correct) = %v, %v", ok, err)
+	}
+	if params != testArgon2idParams {
+		t.Errorf("params = %+v, want %+v", params, testArgon2idParams)
+	}
+
+	ok, _, err = verifyArgon2id("wrong horse", hash)
+	if err != nil || ok {
+		t.Errorf("verifyArgon2id(wrong) = %v, %v", ok, err)
+	}
+}
+
+func TestArgon2idSaltIsRandom(t *testing.T) {
+	first, _ := hashArgon2id("password", testArgon2idParams)
+	second, _ := hashArgon2id("password", testArgon2idParams)
+	if first == second {
+		t.Error("two hashes of the same password are equal")
+	}
+}
+
+func TestVerifyArgon2idRejectsInvalidHashes(t *testing.T) {
+	salt := base64.RawStdEncoding.EncodeToString(make([]byte, 16))
+	key := base64.RawStdEncoding.EncodeToString(make([]byte, 32))
+
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{"too few parts", "$argon2id$v=19$m=64,t=1,p=1$" + salt},
+		{"unsupported version", "$argon2id$v=16$m=64,t=1,p=1$" + salt + "$" + key},
+		{"malformed parameters", "$argon2id$v=19$m=64;t=1;p=1$" + salt + "$" + key},
+		{"zero parallelism", "$argon2id$v=19$m=64,t=1,p=0$" + salt + "$" + key},
+		{"parallelism overflow", "$argon2id$v=19$m=64,t=1,p=256$" + salt + "$" + key},
+		{"zero iterations", "$argon2id$v=19$m=64,t=0,p=1$" + salt + "$" + key},
+		{"too many iterations", "$argon2id$v=19$m=64,t=1000000,p=1$" + salt + "$" + key},
+		{"huge memory", "$argon2id$v=19$m=4294967295,t=1,p=1$" + salt + "$" + key},
+		{"invalid salt", "$argon2id$v=19$m=64,t=1,p=1$!!!$" + key},
+		{"short salt", "$argon2id$v=19$m=64,t=1,p=1$AAAA$" + key},
+		{"invalid key", "$argon2id$v=19$m=64,t=1,p=1$" + salt + "$!!!"},
+		{"empty key", "$argon2id$v=19$m=64,t=1,p=1$" + salt + "$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, _, err := verifyArgon2id("password", tt.hash)
+			if err == nil || ok {
+				t.Errorf("verifyArgon2id(%q) = %v, %v; want error", tt.hash, ok, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BreachedList проверяет пароли по локальной копии базы утёкших паролей в формате
+// k-anonymity диапазонов Have I Been Pwned: файл <первые 5 символов SHA-1>.txt
+// со строками "<остальные 35 символов>:<число утечек>". Сам пароль и полный хэш
+// никуда не передаются, читается только файл нужного префикса
+type BreachedList struct {
+	dir string
+}
+
+func NewBreachedList(dir string) *BreachedList {
+	return &BreachedList{dir: dir}
+}
+
+// Contains возвращает true, если SHA-1 пароля есть в базе. Отсутствующий файл диапазона
+// означает, что утечек с таким префиксом нет (допускается неполная копия базы)
+func (b *BreachedList) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	file, err := os.Open(filepath.Join(b.dir, prefix+".txt"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false
+	}
+	if err != nil {
+		log.Printf("Failed to read breached password range %s: %v", prefix, err)
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		candidate, _, _ := strings.Cut(line, ":")
+		if strings.EqualFold(candidate, suffix) {
+			return true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("Failed to read breached password range %s: %v", prefix, err)
+	}
+	return false
+}
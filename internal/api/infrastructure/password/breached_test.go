@@ -0,0 +1,31 @@
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBreachedListContains(t *testing.T) {
+	dir := t.TempDir()
+	// SHA-1("password") = 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+	ranges := "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n1e4c9b93f3f0682250b6cf8331b7ee68fd8:9545824\r\n"
+	if err := os.WriteFile(filepath.Join(dir, "5BAA6.txt"), []byte(ranges), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	list := NewBreachedList(dir)
+
+	tests := []struct {
+		password string
+		want     bool
+	}{
+		{"password", true},  // суффикс в нижнем регистре
+		{"Password", false}, // другой префикс, файла диапазона нет
+		{"correct horse staple", false},
+	}
+	for _, tt := range tests {
+		if got := list.Contains(tt.password); got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+}
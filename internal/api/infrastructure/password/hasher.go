@@ -0,0 +1,70 @@
+package password
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Hasher хэширует пароли Argon2id и проверяет как Argon2id, так и старые bcrypt-хэши
+type Hasher struct {
+	params Argon2idParams
+}
+
+func NewHasher(params Argon2idParams) *Hasher {
+	return &Hasher{params: params}
+}
+
+// NewHasherFromEnv создаёт Hasher с параметрами Argon2id из ARGON2_* переменных окружения
+func NewHasherFromEnv() *Hasher {
+	params := DefaultArgon2idParams
+	params.Memory = uint32(envUint("ARGON2_MEMORY_KB", uint64(params.Memory)))
+	params.Iterations = uint32(envUint("ARGON2_ITERATIONS", uint64(params.Iterations)))
+	params.Parallelism = uint8(envUint("ARGON2_PARALLELISM", uint64(params.Parallelism)))
+	if err := params.validate(); err != nil {
+		log.Printf("Invalid ARGON2_* settings, using defaults: %v", err)
+		params = DefaultArgon2idParams
+	}
+	return NewHasher(params)
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	return hashArgon2id(password, h.params)
+}
+
+func (h *Hasher) Verify(password, hash string) (bool, bool, error) {
+	switch {
+	case hash == "":
+		// Аккаунт без пароля (создан через OAuth)
+		return false, false, nil
+	case strings.HasPrefix(hash, "$argon2id$"):
+		ok, params, err := verifyArgon2id(password, hash)
+		if err != nil || !ok {
+			return false, false, err
+		}
+		return true, params != h.params, nil
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return false, false, nil
+		}
+		if err != nil {
+			return false, false, err
+		}
+		// bcrypt — устаревший алгоритм, после входа хэш переводится на Argon2id
+		return true, true, nil
+	default:
+		return false, false, fmt.Errorf("Unknown password hash format")
+	}
+}
+
+func envUint(name string, def uint64) uint64 {
+	if value, err := strconv.ParseUint(os.Getenv(name), 10, 32); err == nil && value > 0 {
+		return value
+	}
+	return def
+}
@@ -0,0 +1,67 @@
+package password
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHasherVerify(t *testing.T) {
+	hasher := NewHasher(testArgon2idParams)
+
+	current, err := hasher.Hash("secret password")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	weaker := testArgon2idParams
+	weaker.Memory = 32
+	outdated, err := hashArgon2id("secret password", weaker)
+	if err != nil {
+		t.Fatalf("hashArgon2id: %v", err)
+	}
+	legacy, err := bcrypt.GenerateFromPassword([]byte("secret password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		password        string
+		hash            string
+		wantOK          bool
+		wantNeedsRehash bool
+		wantErr         bool
+	}{
+		{"current params", "secret password", current, true, false, false},
+		{"current params, wrong password", "other password", current, false, false, false},
+		{"outdated params", "secret password", outdated, true, true, false},
+		{"outdated params, wrong password", "other password", outdated, false, false, false},
+		{"bcrypt", "secret password", string(legacy), true, true, false},
+		{"bcrypt, wrong password", "other password", string(legacy), false, false, false},
+		{"no password", "secret password", "", false, false, false},
+		{"unknown format", "secret password", "$md5$abc", false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, needsRehash, err := hasher.Verify(tt.password, tt.hash)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ok != tt.wantOK || needsRehash != tt.wantNeedsRehash {
+				t.Errorf("Verify = (%v, %v), want (%v, %v)", ok, needsRehash, tt.wantOK, tt.wantNeedsRehash)
+			}
+		})
+	}
+}
+
+func TestNewHasherFromEnvRejectsUnsafeParams(t *testing.T) {
+	t.Setenv("ARGON2_MEMORY_KB", "8388608")
+	if hasher := NewHasherFromEnv(); hasher.params != DefaultArgon2idParams {
+		t.Errorf("params = %+v, want defaults", hasher.params)
+	}
+
+	t.Setenv("ARGON2_MEMORY_KB", "32768")
+	if hasher := NewHasherFromEnv(); hasher.params.Memory != 32768 {
+		t.Errorf("memory = %d, want 32768", hasher.params.Memory)
+	}
+}
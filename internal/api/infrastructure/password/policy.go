@@ -0,0 +1,141 @@
+package password
+
+import (
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Коды нарушений политики паролей
+const (
+	ViolationTooShort      = "password_too_short"
+	ViolationTooLong       = "password_too_long"
+	ViolationTooWeak       = "password_too_weak"
+	ViolationContainsEmail = "password_contains_email"
+	ViolationBreached      = "password_breached"
+)
+
+// Violation — причина, по которой пароль не принят
+type Violation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Policy — требования к новым паролям
+type Policy struct {
+	MinLength      int
+	MaxLength      int
+	MinEntropyBits float64
+	Breached       *BreachedList // nil — проверка по утечкам отключена
+}
+
+// NewPolicyFromEnv создаёт политику из PASSWORD_* переменных окружения
+func NewPolicyFromEnv() *Policy {
+	policy := &Policy{
+		MinLength:      envInt("PASSWORD_MIN_LENGTH", 8),
+		MaxLength:      envInt("PASSWORD_MAX_LENGTH", 128),
+		MinEntropyBits: float64(envInt("PASSWORD_MIN_ENTROPY_BITS", 40)),
+	}
+	if dir := os.Getenv("BREACHED_PASSWORDS_DIR"); dir != "" {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			log.Printf("BREACHED_PASSWORDS_DIR %q is not a directory, breached password check is disabled", dir)
+		} else {
+			policy.Breached = NewBreachedList(dir)
+		}
+	}
+	return policy
+}
+
+// Validate проверяет пароль и возвращает все нарушения (пустой срез — пароль подходит).
+// email может быть пустым, тогда сравнение с email пропускается
+func (p *Policy) Validate(password, email string) []Violation {
+	var violations []Violation
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		violations = append(violations, Violation{ViolationTooShort, "Password must be at least " + strconv.Itoa(p.MinLength) + " characters long"})
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		violations = append(violations, Violation{ViolationTooLong, "Password must be at most " + strconv.Itoa(p.MaxLength) + " characters long"})
+	}
+	if length >= p.MinLength && EstimateEntropy(password) < p.MinEntropyBits {
+		violations = append(violations, Violation{ViolationTooWeak, "Password is too easy to guess, use a longer or more varied password"})
+	}
+	if containsEmail(password, email) {
+		violations = append(violations, Violation{ViolationContainsEmail, "Password must not contain your email address"})
+	}
+	if p.Breached != nil && password != "" && p.Breached.Contains(password) {
+		violations = append(violations, Violation{ViolationBreached, "Password has appeared in a data breach, choose a different one"})
+	}
+
+	return violations
+}
+
+// EstimateEntropy грубо оценивает энтропию пароля в битах: log2 размера алфавита
+// по использованным классам символов, умноженный на число "значимых" символов.
+// Повторы и последовательности (aaa, abc, 321) символами не считаются
+func EstimateEntropy(password string) float64 {
+	var lower, upper, digit, symbol, other bool
+	effective := 0
+	var prev, prevStep rune
+	for i, r := range []rune(password) {
+		switch {
+		case r < unicode.MaxASCII && unicode.IsLower(r):
+			lower = true
+		case r < unicode.MaxASCII && unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case r < unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+
+		step := r - prev
+		if i == 0 || (step != 0 && !(i > 1 && step == prevStep && (step == 1 || step == -1))) {
+			effective++
+		}
+		prev, prevStep = r, step
+	}
+
+	pool := 0
+	for _, class := range []struct {
+		used bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if class.used {
+			pool += class.size
+		}
+	}
+	if pool == 0 {
+		return 0
+	}
+	return float64(effective) * math.Log2(float64(pool))
+}
+
+// containsEmail проверяет, совпадает ли пароль с email или его частью до "@"
+func containsEmail(password, email string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return false
+	}
+	password = strings.ToLower(password)
+	if strings.Contains(password, email) {
+		return true
+	}
+	local, _, _ := strings.Cut(email, "@")
+	// Короткие имена ("ann") встречаются в паролях случайно
+	return password == local || (utf8.RuneCountInString(local) >= 4 && strings.Contains(password, local))
+}
+
+func envInt(name string, def int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value >= 0 {
+		return value
+	}
+	return def
+}
@@ -0,0 +1,68 @@
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	dir := t.TempDir()
+	// SHA-1("password") = 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+	if err := os.WriteFile(filepath.Join(dir, "5BAA6.txt"), []byte("1E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\r\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	policy := &Policy{MinLength: 8, MaxLength: 64, MinEntropyBits: 40, Breached: NewBreachedList(dir)}
+
+	tests := []struct {
+		name     string
+		password string
+		email    string
+		want     []string
+	}{
+		{"strong", "Tr0ub4dor&3-staple", "ann@example.com", nil},
+		{"too short", "aB3$", "", []string{ViolationTooShort}},
+		{"too long", strings.Repeat("Tr0ub4dor&3-", 6), "", []string{ViolationTooLong}},
+		{"repeated characters", "aaaaaaaaaaaaaaaa", "", []string{ViolationTooWeak}},
+		{"sequence", "abcdefghijklmnop", "", []string{ViolationTooWeak}},
+		{"contains email", "xX-alice@example.com-Xx", "Alice@Example.com", []string{ViolationContainsEmail}},
+		{"contains local part", "Qz7!aliceWonder", "alice@example.com", []string{ViolationContainsEmail}},
+		{"short local part is allowed", "Qz7!annWonder9", "ann@example.com", nil},
+		{"breached", "password", "", []string{ViolationTooWeak, ViolationBreached}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, violation := range policy.Validate(tt.password, tt.email) {
+				got = append(got, violation.Code)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("violations = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("violations = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEstimateEntropy(t *testing.T) {
+	tests := []struct {
+		password string
+		min, max float64
+	}{
+		{"", 0, 0},
+		{"aaaaaaaa", 4, 5}, // один значимый символ из 26
+		{"12345678", 6, 7}, // последовательность: значимы два первых символа
+		{"correcthorsebatterystaple", 100, 120},
+		{"Tr0ub4dor&3", 70, 75},
+	}
+	for _, tt := range tests {
+		if got := EstimateEntropy(tt.password); got < tt.min || got > tt.max {
+			t.Errorf("EstimateEntropy(%q) = %.1f, want between %.0f and %.0f", tt.password, got, tt.min, tt.max)
+		}
+	}
+}
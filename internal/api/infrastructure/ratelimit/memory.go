@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Как часто MemoryStore удаляет ключи без актуальных попыток
+const memorySweepInterval = time.Minute
+
+// MemoryStore — хранилище попыток в памяти процесса. Используется, когда Redis не настроен;
+// счётчики не разделяются между экземплярами API и сбрасываются при перезапуске
+type MemoryStore struct {
+	mu        sync.Mutex
+	attempts  map[string][]time.Time
+	expires   map[string]time.Time
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		attempts:  make(map[string][]time.Time),
+		expires:   make(map[string]time.Time),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryStore) AddAttempt(ctx context.Context, key string, at time.Time, window time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(at)
+	s.attempts[key] = append(prune(s.attempts[key], at.Add(-window)), at)
+	s.expires[key] = at.Add(window)
+	return nil
+}
+
+func (s *MemoryStore) Attempts(ctx context.Context, key string, window time.Duration) ([]time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempts := prune(s.attempts[key], time.Now().Add(-window))
+	return append([]time.Time(nil), attempts...), nil
+}
+
+func (s *MemoryStore) ResetAttempts(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	delete(s.expires, key)
+	return nil
+}
+
+// sweep удаляет ключи, у которых истекло окно, не чаще memorySweepInterval
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, expiresAt := range s.expires {
+		if now.After(expiresAt) {
+			delete(s.attempts, key)
+			delete(s.expires, key)
+		}
+	}
+}
+
+// prune отбрасывает попытки раньше since (срез отсортирован по времени)
+func prune(attempts []time.Time, since time.Time) []time.Time {
+	for i, at := range attempts {
+		if !at.Before(since) {
+			return attempts[i:]
+		}
+	}
+	return nil
+}
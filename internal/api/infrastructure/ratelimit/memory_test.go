@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	window := time.Minute
+	now := time.Now()
+
+	store.AddAttempt(ctx, "key", now.Add(-2*window), window)
+	store.AddAttempt(ctx, "key", now.Add(-30*time.Second), window)
+	store.AddAttempt(ctx, "key", now, window)
+	store.AddAttempt(ctx, "other", now, window)
+
+	attempts, err := store.Attempts(ctx, "key", window)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attempts) != 2 || !attempts[0].Equal(now.Add(-30*time.Second)) || !attempts[1].Equal(now) {
+		t.Errorf("Attempts = %v, want the last two attempts in order", attempts)
+	}
+
+	// Возвращается копия: изменение не затрагивает хранилище
+	attempts[0] = time.Time{}
+	if again, _ := store.Attempts(ctx, "key", window); again[0].IsZero() {
+		t.Error("Attempts returned the internal slice")
+	}
+
+	store.ResetAttempts(ctx, "key")
+	if attempts, _ := store.Attempts(ctx, "key", window); len(attempts) != 0 {
+		t.Errorf("Attempts after reset = %v, want none", attempts)
+	}
+	if attempts, _ := store.Attempts(ctx, "other", window); len(attempts) != 1 {
+		t.Errorf("Attempts for another key = %v, want one", attempts)
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+
+	store.AddAttempt(ctx, "stale", now, time.Second)
+	store.AddAttempt(ctx, "fresh", now.Add(memorySweepInterval), time.Hour)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if _, ok := store.attempts["stale"]; ok {
+		t.Error("sweep kept a key with an expired window")
+	}
+	if _, ok := store.attempts["fresh"]; !ok {
+		t.Error("sweep removed a key with a live window")
+	}
+}
@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oziev02/checklist-microservices/internal/api/ports"
+)
+
+// Префиксы ключей неудачных попыток
+const (
+	accountKeyPrefix = "failed_attempts:account:"
+	ipKeyPrefix      = "failed_attempts:ip:"
+)
+
+// Throttler ограничивает перебор паролей и кодов: после каждой неудачи сверх FreeAttempts
+// следующая попытка разрешается с растущей задержкой, после MaxAccountFailures аккаунт
+// блокируется на LockoutDuration, а с одного IP допускается не более MaxIPFailures неудач за окно
+type Throttler struct {
+	store ports.AttemptStore
+
+	Window             time.Duration // окно, в котором считаются неудачи
+	FreeAttempts       int           // неудач без задержки
+	MaxDelay           time.Duration // предел прогрессивной задержки
+	MaxAccountFailures int
+	LockoutDuration    time.Duration
+	MaxIPFailures      int
+}
+
+// NewThrottlerFromEnv создаёт Throttler с настройками из LOGIN_* переменных окружения
+func NewThrottlerFromEnv(store ports.AttemptStore) *Throttler {
+	return &Throttler{
+		store:              store,
+		Window:             envDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		FreeAttempts:       envInt("LOGIN_FREE_ATTEMPTS", 3),
+		MaxDelay:           envDuration("LOGIN_MAX_DELAY", 30*time.Second),
+		MaxAccountFailures: envInt("LOGIN_MAX_FAILURES", 10),
+		LockoutDuration:    envDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+		MaxIPFailures:      envInt("LOGIN_MAX_FAILURES_PER_IP", 50),
+	}
+}
+
+// Check возвращает, сколько нужно подождать перед следующей попыткой для аккаунта
+// (email или другой идентификатор) и IP. Ноль — попытка разрешена
+func (t *Throttler) Check(ctx context.Context, account, ip string) (time.Duration, error) {
+	now := time.Now()
+
+	accountAttempts, err := t.store.Attempts(ctx, accountKey(account), t.Window)
+	if err != nil {
+		return 0, err
+	}
+	wait := t.accountWait(accountAttempts, now)
+
+	ipAttempts, err := t.store.Attempts(ctx, ipKeyPrefix+ip, t.Window)
+	if err != nil {
+		return 0, err
+	}
+	if n := len(ipAttempts); t.MaxIPFailures > 0 && n >= t.MaxIPFailures {
+		// Следующая попытка станет возможной, когда самая старая из последних MaxIPFailures выйдет из окна
+		if ipWait := ipAttempts[n-t.MaxIPFailures].Add(t.Window).Sub(now); ipWait > wait {
+			wait = ipWait
+		}
+	}
+
+	if wait < 0 {
+		return 0, nil
+	}
+	return wait, nil
+}
+
+// Failure записывает неудачную попытку для аккаунта и IP
+func (t *Throttler) Failure(ctx context.Context, account, ip string) error {
+	now := time.Now()
+	if err := t.store.AddAttempt(ctx, accountKey(account), now, t.Window); err != nil {
+		return err
+	}
+	return t.store.AddAttempt(ctx, ipKeyPrefix+ip, now, t.Window)
+}
+
+// Success сбрасывает счётчик аккаунта. Счётчик IP не сбрасывается, чтобы успешный вход
+// в свой аккаунт не открывал перебор чужих
+func (t *Throttler) Success(ctx context.Context, account string) error {
+	return t.store.ResetAttempts(ctx, accountKey(account))
+}
+
+// accountWait считает блокировку или прогрессивную задержку от последней неудачи
+func (t *Throttler) accountWait(attempts []time.Time, now time.Time) time.Duration {
+	n := len(attempts)
+	if n == 0 {
+		return 0
+	}
+	last := attempts[n-1]
+
+	if t.MaxAccountFailures > 0 && n >= t.MaxAccountFailures {
+		return last.Add(t.LockoutDuration).Sub(now)
+	}
+	if n <= t.FreeAttempts {
+		return 0
+	}
+
+	// 1с, 2с, 4с, ... но не больше MaxDelay
+	delay := t.MaxDelay
+	if shift := n - t.FreeAttempts - 1; shift < 16 {
+		if d := time.Second << shift; d < delay {
+			delay = d
+		}
+	}
+	return last.Add(delay).Sub(now)
+}
+
+func accountKey(account string) string {
+	return accountKeyPrefix + strings.ToLower(account)
+}
+
+func envInt(name string, def int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil {
+		return value
+	}
+	return def
+}
+
+func envDuration(name string, def time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(name)); err == nil {
+		return value
+	}
+	return def
+}
@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestThrottler() *Throttler {
+	return &Throttler{
+		store:              NewMemoryStore(),
+		Window:             15 * time.Minute,
+		FreeAttempts:       3,
+		MaxDelay:           30 * time.Second,
+		MaxAccountFailures: 10,
+		LockoutDuration:    15 * time.Minute,
+		MaxIPFailures:      5,
+	}
+}
+
+func TestAccountWait(t *testing.T) {
+	throttler := newTestThrottler()
+	now := time.Now()
+
+	// failures неудач, последняя — только что
+	attempts := func(failures int) []time.Time {
+		list := make([]time.Time, failures)
+		for i := range list {
+			list[i] = now.Add(time.Duration(i-failures+1) * time.Second)
+		}
+		return list
+	}
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, 0},
+		{3, 0}, // FreeAttempts
+		{4, time.Second},
+		{5, 2 * time.Second},
+		{6, 4 * time.Second},
+		{8, 16 * time.Second},
+		{9, 30 * time.Second},  // MaxDelay
+		{10, 15 * time.Minute}, // MaxAccountFailures: блокировка
+		{25, 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := throttler.accountWait(attempts(tt.failures), now); got != tt.want {
+			t.Errorf("accountWait(%d failures) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+
+	// Задержка отсчитывается от последней неудачи
+	last := []time.Time{now.Add(-10 * time.Second), now.Add(-9 * time.Second), now.Add(-8 * time.Second), now.Add(-7 * time.Second), now.Add(-time.Second)}
+	if got := throttler.accountWait(last, now); got != time.Second {
+		t.Errorf("accountWait after 1s = %v, want 1s", got)
+	}
+}
+
+func TestThrottlerAccount(t *testing.T) {
+	ctx := context.Background()
+	throttler := newTestThrottler()
+	throttler.MaxIPFailures = 0
+
+	for i := 0; i < throttler.FreeAttempts; i++ {
+		if wait, err := throttler.Check(ctx, "User@Example.com", "10.0.0.1"); err != nil || wait != 0 {
+			t.Fatalf("Check before failure %d = %v, %v", i+1, wait, err)
+		}
+		throttler.Failure(ctx, "user@example.com", "10.0.0.1")
+	}
+	throttler.Failure(ctx, "USER@example.com", "10.0.0.2")
+
+	// Аккаунт считается без учёта регистра и независимо от IP
+	if wait, _ := throttler.Check(ctx, "user@example.com", "10.0.0.3"); wait <= 0 || wait > time.Second {
+		t.Errorf("Check after 4 failures = %v, want up to 1s", wait)
+	}
+	if wait, _ := throttler.Check(ctx, "other@example.com", "10.0.0.3"); wait != 0 {
+		t.Errorf("Check for another account = %v, want 0", wait)
+	}
+
+	throttler.Success(ctx, "user@example.com")
+	if wait, _ := throttler.Check(ctx, "user@example.com", "10.0.0.3"); wait != 0 {
+		t.Errorf("Check after success = %v, want 0", wait)
+	}
+}
+
+func TestThrottlerIPWindow(t *testing.T) {
+	ctx := context.Background()
+	throttler := newTestThrottler()
+	store := throttler.store.(*MemoryStore)
+	now := time.Now()
+
+	// Самая старая из последних MaxIPFailures неудач выходит из окна через минуту
+	for i := 0; i < throttler.MaxIPFailures; i++ {
+		at := now.Add(-throttler.Window + time.Minute + time.Duration(i)*time.Second)
+		store.AddAttempt(ctx, ipKeyPrefix+"10.0.0.1", at, throttler.Window)
+	}
+
+	wait, err := throttler.Check(ctx, "fresh@example.com", "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait < 59*time.Second || wait > time.Minute {
+		t.Errorf("Check for blocked IP = %v, want about 1m", wait)
+	}
+	if wait, _ := throttler.Check(ctx, "fresh@example.com", "10.0.0.2"); wait != 0 {
+		t.Errorf("Check for another IP = %v, want 0", wait)
+	}
+
+	// Успешный вход не сбрасывает счётчик IP
+	throttler.Success(ctx, "fresh@example.com")
+	if wait, _ := throttler.Check(ctx, "fresh@example.com", "10.0.0.1"); wait == 0 {
+		t.Error("Success reset the IP counter")
+	}
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// AttemptStore хранит время попыток в скользящем окне: неудачные входы и проверки 2FA,
+// отправленные письма
+type AttemptStore interface {
+	// AddAttempt записывает попытку и удаляет записи старше window
+	AddAttempt(ctx context.Context, key string, at time.Time, window time.Duration) error
+	// Attempts возвращает время попыток за последние window по возрастанию
+	Attempts(ctx context.Context, key string, window time.Duration) ([]time.Time, error)
+	ResetAttempts(ctx context.Context, key string) error
+}
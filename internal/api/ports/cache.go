@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Cache — хранилище короткоживущих данных (Redis)
+type Cache interface {
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	// Get возвращает пустую строку, если ключа нет
+	Get(ctx context.Context, key string) (string, error)
+	Delete(ctx context.Context, key string) error
+	// SetIfAbsent атомарно сохраняет значение, только если ключа ещё нет. false — ключ уже был
+	SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+}
@@ -0,0 +1,8 @@
+package ports
+
+import "context"
+
+// Mailer отправляет письма пользователям (сброс пароля, подтверждение email и т.п.)
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
@@ -0,0 +1,10 @@
+package ports
+
+// PasswordHasher хэширует и проверяет пароли. Хэши самоописываемые (формат PHC или bcrypt),
+// поэтому проверка работает и для хэшей, созданных с прежними параметрами
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify сравнивает пароль с хэшем. needsRehash — хэш создан устаревшим алгоритмом
+	// или параметрами и после успешной проверки его стоит пересчитать
+	Verify(password, hash string) (ok bool, needsRehash bool, err error)
+}
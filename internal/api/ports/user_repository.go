@@ -9,4 +9,6 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, email, password string) (*entities.User, error)
 	UpdateProfile(ctx context.Context, userID, avatar, description string, socials map[string]string) (*entities.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*entities.User, error)
+	GetUserByID(ctx context.Context, userID string) (*entities.User, error)
+	UpdateTwoFA(ctx context.Context, userID string, enabled bool, secret string) (*entities.User, error)
 }
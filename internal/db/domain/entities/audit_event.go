@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Результаты действий в журнале аудита
+const (
+	AuditResultSuccess = "success"
+	AuditResultFailure = "failure"
+)
+
+// AuditEvent — запись журнала аудита: кто (ActorID), что сделал (Action) и с кем (TargetID)
+type AuditEvent struct {
+	ID        uuid.UUID         `json:"id"`
+	ActorID   *uuid.UUID        `json:"actor_id"`  // nil — действие без аутентифицированного пользователя
+	Action    string            `json:"action"`    // например admin.user.disable
+	TargetID  *uuid.UUID        `json:"target_id"` // nil — действие не над пользователем
+	IP        string            `json:"ip"`
+	UserAgent string            `json:"user_agent"`
+	Result    string            `json:"result"`
+	Details   map[string]string `json:"details"` // параметры действия, например фильтры поиска
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// AuditEventFilter — условия выборки из журнала аудита; пустые поля не ограничивают выборку
+type AuditEventFilter struct {
+	ActorID  string
+	TargetID string
+	Action   string
+	Result   string
+	Since    time.Time // включительно
+	Until    time.Time // не включительно
+}
@@ -0,0 +1,16 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Identity — учётная запись пользователя у внешнего OAuth-провайдера
+type Identity struct {
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"` // Идентификатор пользователя у провайдера
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
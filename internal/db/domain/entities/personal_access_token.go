@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersonalAccessToken — долгоживущий токен для скриптов и интеграций.
+// Сам токен показывается один раз при создании, в БД хранится только его хэш
+type PersonalAccessToken struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`   // nil — бессрочный
+	LastUsedAt *time.Time `json:"last_used_at"` // nil — ещё не использовался
+	CreatedAt  time.Time  `json:"created_at"`
+}
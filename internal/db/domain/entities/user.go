@@ -1,27 +1,52 @@
 package entities
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы аккаунта
+const (
+	UserStatusActive          = "active"
+	UserStatusSuspended       = "suspended"        // отключён администратором
+	UserStatusPendingDeletion = "pending_deletion" // удаление запланировано, вход до срока отменяет его
+)
+
+// Роли пользователя
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
 
 type User struct {
-	ID           uuid.UUID         `json:"id"`
-	Email        string            `json:"email"`
-	Password     string            `json:"password"`
-	Avatar       string            `json:"avatar"`
-	Description  string            `json:"description"`
-	Socials      map[string]string `json:"socials"`
-	TwoFAEnabled bool              `json:"twofa_enabled"`
-	TwoFASecret  string            `json:"twofa_secret"`
+	ID            uuid.UUID         `json:"id"`
+	Email         string            `json:"email"`
+	Password      string            `json:"password"`
+	Avatar        string            `json:"avatar"`
+	Description   string            `json:"description"`
+	Socials       map[string]string `json:"socials"`
+	TwoFAEnabled  bool              `json:"twofa_enabled"`
+	TwoFASecret   string            `json:"twofa_secret"`
+	EmailVerified bool              `json:"email_verified"`
+	Role          string            `json:"role"`
+
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at"` // nil — удаление не запрошено
+	Status              string     `json:"status"`
 }
 
 func NewUser(email, password string) *User {
 	return &User{
-		ID:           uuid.New(),
-		Email:        email,
-		Password:     password,
-		Avatar:       "",
-		Description:  "",
-		Socials:      make(map[string]string),
-		TwoFAEnabled: false,
-		TwoFASecret:  "",
+		ID:            uuid.New(),
+		Email:         email,
+		Password:      password,
+		Avatar:        "",
+		Description:   "",
+		Socials:       make(map[string]string),
+		TwoFAEnabled:  false,
+		TwoFASecret:   "",
+		EmailVerified: false,
+		Role:          RoleUser,
+		Status:        UserStatusActive,
 	}
 }
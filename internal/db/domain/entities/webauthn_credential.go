@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebAuthnCredential — passkey пользователя. Data хранится как есть: разбирает
+// и проверяет его API-сервис, БД отвечает только за владельца и поиск по ID ключа
+type WebAuthnCredential struct {
+	CredentialID string     `json:"credential_id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	Name         string     `json:"name"`
+	Data         []byte     `json:"data"`
+	LastUsedAt   *time.Time `json:"last_used_at"` // nil — ещё не использовался
+	CreatedAt    time.Time  `json:"created_at"`
+}
@@ -0,0 +1,11 @@
+package errors
+
+import "errors"
+
+var (
+	// ErrEmailTaken — email уже принадлежит другому аккаунту
+	ErrEmailTaken = errors.New("email is already registered")
+
+	// ErrCredentialExists — passkey с таким ID уже зарегистрирован
+	ErrCredentialExists = errors.New("credential is already registered")
+)
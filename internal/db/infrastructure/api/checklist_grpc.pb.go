@@ -19,13 +19,49 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ChecklistService_CreateTask_FullMethodName     = "/checklist.ChecklistService/CreateTask"
-	ChecklistService_ListTasks_FullMethodName      = "/checklist.ChecklistService/ListTasks"
-	ChecklistService_DeleteTask_FullMethodName     = "/checklist.ChecklistService/DeleteTask"
-	ChecklistService_MarkTaskDone_FullMethodName   = "/checklist.ChecklistService/MarkTaskDone"
-	ChecklistService_CreateUser_FullMethodName     = "/checklist.ChecklistService/CreateUser"
-	ChecklistService_UpdateProfile_FullMethodName  = "/checklist.ChecklistService/UpdateProfile"
-	ChecklistService_GetUserByEmail_FullMethodName = "/checklist.ChecklistService/GetUserByEmail"
+	ChecklistService_CreateTask_FullMethodName                      = "/checklist.ChecklistService/CreateTask"
+	ChecklistService_ListTasks_FullMethodName                       = "/checklist.ChecklistService/ListTasks"
+	ChecklistService_DeleteTask_FullMethodName                      = "/checklist.ChecklistService/DeleteTask"
+	ChecklistService_MarkTaskDone_FullMethodName                    = "/checklist.ChecklistService/MarkTaskDone"
+	ChecklistService_CreateUser_FullMethodName                      = "/checklist.ChecklistService/CreateUser"
+	ChecklistService_UpdateProfile_FullMethodName                   = "/checklist.ChecklistService/UpdateProfile"
+	ChecklistService_GetUserByEmail_FullMethodName                  = "/checklist.ChecklistService/GetUserByEmail"
+	ChecklistService_GetUserByID_FullMethodName                     = "/checklist.ChecklistService/GetUserByID"
+	ChecklistService_UpdateTwoFA_FullMethodName                     = "/checklist.ChecklistService/UpdateTwoFA"
+	ChecklistService_UpdatePassword_FullMethodName                  = "/checklist.ChecklistService/UpdatePassword"
+	ChecklistService_RehashPassword_FullMethodName                  = "/checklist.ChecklistService/RehashPassword"
+	ChecklistService_ScheduleAccountDeletion_FullMethodName         = "/checklist.ChecklistService/ScheduleAccountDeletion"
+	ChecklistService_CancelAccountDeletion_FullMethodName           = "/checklist.ChecklistService/CancelAccountDeletion"
+	ChecklistService_ExportUserData_FullMethodName                  = "/checklist.ChecklistService/ExportUserData"
+	ChecklistService_ListUsers_FullMethodName                       = "/checklist.ChecklistService/ListUsers"
+	ChecklistService_SetUserStatus_FullMethodName                   = "/checklist.ChecklistService/SetUserStatus"
+	ChecklistService_ResetTwoFactor_FullMethodName                  = "/checklist.ChecklistService/ResetTwoFactor"
+	ChecklistService_DeleteUser_FullMethodName                      = "/checklist.ChecklistService/DeleteUser"
+	ChecklistService_RecordAuditEvent_FullMethodName                = "/checklist.ChecklistService/RecordAuditEvent"
+	ChecklistService_ListAuditEvents_FullMethodName                 = "/checklist.ChecklistService/ListAuditEvents"
+	ChecklistService_ReplaceRecoveryCodes_FullMethodName            = "/checklist.ChecklistService/ReplaceRecoveryCodes"
+	ChecklistService_UseRecoveryCode_FullMethodName                 = "/checklist.ChecklistService/UseRecoveryCode"
+	ChecklistService_UseTOTPStep_FullMethodName                     = "/checklist.ChecklistService/UseTOTPStep"
+	ChecklistService_CreateRefreshFamily_FullMethodName             = "/checklist.ChecklistService/CreateRefreshFamily"
+	ChecklistService_RotateRefreshToken_FullMethodName              = "/checklist.ChecklistService/RotateRefreshToken"
+	ChecklistService_RevokeRefreshFamily_FullMethodName             = "/checklist.ChecklistService/RevokeRefreshFamily"
+	ChecklistService_RevokeUserRefreshFamilies_FullMethodName       = "/checklist.ChecklistService/RevokeUserRefreshFamilies"
+	ChecklistService_ListSessions_FullMethodName                    = "/checklist.ChecklistService/ListSessions"
+	ChecklistService_SignInWithIdentity_FullMethodName              = "/checklist.ChecklistService/SignInWithIdentity"
+	ChecklistService_CreateEmailToken_FullMethodName                = "/checklist.ChecklistService/CreateEmailToken"
+	ChecklistService_GetUserByEmailToken_FullMethodName             = "/checklist.ChecklistService/GetUserByEmailToken"
+	ChecklistService_ResetPassword_FullMethodName                   = "/checklist.ChecklistService/ResetPassword"
+	ChecklistService_VerifyEmail_FullMethodName                     = "/checklist.ChecklistService/VerifyEmail"
+	ChecklistService_ConsumeMagicLink_FullMethodName                = "/checklist.ChecklistService/ConsumeMagicLink"
+	ChecklistService_CreatePersonalAccessToken_FullMethodName       = "/checklist.ChecklistService/CreatePersonalAccessToken"
+	ChecklistService_ListPersonalAccessTokens_FullMethodName        = "/checklist.ChecklistService/ListPersonalAccessTokens"
+	ChecklistService_RevokePersonalAccessToken_FullMethodName       = "/checklist.ChecklistService/RevokePersonalAccessToken"
+	ChecklistService_AuthenticatePersonalAccessToken_FullMethodName = "/checklist.ChecklistService/AuthenticatePersonalAccessToken"
+	ChecklistService_CreateWebAuthnCredential_FullMethodName        = "/checklist.ChecklistService/CreateWebAuthnCredential"
+	ChecklistService_ListWebAuthnCredentials_FullMethodName         = "/checklist.ChecklistService/ListWebAuthnCredentials"
+	ChecklistService_GetWebAuthnCredential_FullMethodName           = "/checklist.ChecklistService/GetWebAuthnCredential"
+	ChecklistService_UpdateWebAuthnCredentialUsage_FullMethodName   = "/checklist.ChecklistService/UpdateWebAuthnCredentialUsage"
+	ChecklistService_DeleteWebAuthnCredential_FullMethodName        = "/checklist.ChecklistService/DeleteWebAuthnCredential"
 )
 
 // ChecklistServiceClient is the client API for ChecklistService service.
@@ -39,6 +75,42 @@ type ChecklistServiceClient interface {
 	CreateUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UserResponse, error)
 	UpdateProfile(ctx context.Context, in *UpdateProfileRequest, opts ...grpc.CallOption) (*UserResponse, error)
 	GetUserByEmail(ctx context.Context, in *EmailRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	GetUserByID(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	UpdateTwoFA(ctx context.Context, in *UpdateTwoFARequest, opts ...grpc.CallOption) (*UserResponse, error)
+	UpdatePassword(ctx context.Context, in *UpdatePasswordRequest, opts ...grpc.CallOption) (*Empty, error)
+	RehashPassword(ctx context.Context, in *RehashPasswordRequest, opts ...grpc.CallOption) (*Empty, error)
+	ScheduleAccountDeletion(ctx context.Context, in *ScheduleAccountDeletionRequest, opts ...grpc.CallOption) (*Empty, error)
+	CancelAccountDeletion(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*CancelAccountDeletionResponse, error)
+	ExportUserData(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportUserDataChunk], error)
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	SetUserStatus(ctx context.Context, in *SetUserStatusRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	ResetTwoFactor(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*Empty, error)
+	DeleteUser(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*Empty, error)
+	RecordAuditEvent(ctx context.Context, in *AuditEventRequest, opts ...grpc.CallOption) (*Empty, error)
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
+	ReplaceRecoveryCodes(ctx context.Context, in *ReplaceRecoveryCodesRequest, opts ...grpc.CallOption) (*Empty, error)
+	UseRecoveryCode(ctx context.Context, in *UseRecoveryCodeRequest, opts ...grpc.CallOption) (*UseRecoveryCodeResponse, error)
+	UseTOTPStep(ctx context.Context, in *UseTOTPStepRequest, opts ...grpc.CallOption) (*UseTOTPStepResponse, error)
+	CreateRefreshFamily(ctx context.Context, in *CreateRefreshFamilyRequest, opts ...grpc.CallOption) (*Empty, error)
+	RotateRefreshToken(ctx context.Context, in *RotateRefreshTokenRequest, opts ...grpc.CallOption) (*RotateRefreshTokenResponse, error)
+	RevokeRefreshFamily(ctx context.Context, in *RevokeRefreshFamilyRequest, opts ...grpc.CallOption) (*Empty, error)
+	RevokeUserRefreshFamilies(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*Empty, error)
+	ListSessions(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	SignInWithIdentity(ctx context.Context, in *IdentityRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	CreateEmailToken(ctx context.Context, in *CreateEmailTokenRequest, opts ...grpc.CallOption) (*Empty, error)
+	GetUserByEmailToken(ctx context.Context, in *EmailTokenRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*UserIDResponse, error)
+	VerifyEmail(ctx context.Context, in *VerifyEmailRequest, opts ...grpc.CallOption) (*UserIDResponse, error)
+	ConsumeMagicLink(ctx context.Context, in *MagicLinkRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	CreatePersonalAccessToken(ctx context.Context, in *CreatePersonalAccessTokenRequest, opts ...grpc.CallOption) (*PersonalAccessTokenResponse, error)
+	ListPersonalAccessTokens(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*ListPersonalAccessTokensResponse, error)
+	RevokePersonalAccessToken(ctx context.Context, in *RevokePersonalAccessTokenRequest, opts ...grpc.CallOption) (*Empty, error)
+	AuthenticatePersonalAccessToken(ctx context.Context, in *AuthenticatePersonalAccessTokenRequest, opts ...grpc.CallOption) (*PersonalAccessTokenResponse, error)
+	CreateWebAuthnCredential(ctx context.Context, in *CreateWebAuthnCredentialRequest, opts ...grpc.CallOption) (*WebAuthnCredentialResponse, error)
+	ListWebAuthnCredentials(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*ListWebAuthnCredentialsResponse, error)
+	GetWebAuthnCredential(ctx context.Context, in *WebAuthnCredentialIDRequest, opts ...grpc.CallOption) (*WebAuthnCredentialResponse, error)
+	UpdateWebAuthnCredentialUsage(ctx context.Context, in *UpdateWebAuthnCredentialUsageRequest, opts ...grpc.CallOption) (*Empty, error)
+	DeleteWebAuthnCredential(ctx context.Context, in *DeleteWebAuthnCredentialRequest, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type checklistServiceClient struct {
@@ -119,6 +191,375 @@ func (c *checklistServiceClient) GetUserByEmail(ctx context.Context, in *EmailRe
 	return out, nil
 }
 
+func (c *checklistServiceClient) GetUserByID(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_GetUserByID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) UpdateTwoFA(ctx context.Context, in *UpdateTwoFARequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_UpdateTwoFA_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) UpdatePassword(ctx context.Context, in *UpdatePasswordRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ChecklistService_UpdatePassword_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) RehashPassword(ctx context.Context, in *RehashPasswordRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ChecklistService_RehashPassword_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) ScheduleAccountDeletion(ctx context.Context, in *ScheduleAccountDeletionRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ChecklistService_ScheduleAccountDeletion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) CancelAccountDeletion(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*CancelAccountDeletionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelAccountDeletionResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_CancelAccountDeletion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) ExportUserData(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportUserDataChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChecklistService_ServiceDesc.Streams[0], ChecklistService_ExportUserData_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UserIDRequest, ExportUserDataChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChecklistService_ExportUserDataClient = grpc.ServerStreamingClient[ExportUserDataChunk]
+
+func (c *checklistServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUsersResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_ListUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) SetUserStatus(ctx context.Context, in *SetUserStatusRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_SetUserStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) ResetTwoFactor(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ChecklistService_ResetTwoFactor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) DeleteUser(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ChecklistService_DeleteUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) RecordAuditEvent(ctx context.Context, in *AuditEventRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ChecklistService_RecordAuditEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_ListAuditEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) ReplaceRecoveryCodes(ctx context.Context, in *ReplaceRecoveryCodesRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ChecklistService_ReplaceRecoveryCodes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) UseRecoveryCode(ctx context.Context, in *UseRecoveryCodeRequest, opts ...grpc.CallOption) (*UseRecoveryCodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UseRecoveryCodeResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_UseRecoveryCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) UseTOTPStep(ctx context.Context, in *UseTOTPStepRequest, opts ...grpc.CallOption) (*UseTOTPStepResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UseTOTPStepResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_UseTOTPStep_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) CreateRefreshFamily(ctx context.Context, in *CreateRefreshFamilyRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ChecklistService_CreateRefreshFamily_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) RotateRefreshToken(ctx context.Context, in *RotateRefreshTokenRequest, opts ...grpc.CallOption) (*RotateRefreshTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateRefreshTokenResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_RotateRefreshToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) RevokeRefreshFamily(ctx context.Context, in *RevokeRefreshFamilyRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ChecklistService_RevokeRefreshFamily_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) RevokeUserRefreshFamilies(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ChecklistService_RevokeUserRefreshFamilies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) ListSessions(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) SignInWithIdentity(ctx context.Context, in *IdentityRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_SignInWithIdentity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) CreateEmailToken(ctx context.Context, in *CreateEmailTokenRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ChecklistService_CreateEmailToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) GetUserByEmailToken(ctx context.Context, in *EmailTokenRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_GetUserByEmailToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*UserIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserIDResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_ResetPassword_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) VerifyEmail(ctx context.Context, in *VerifyEmailRequest, opts ...grpc.CallOption) (*UserIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserIDResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_VerifyEmail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) ConsumeMagicLink(ctx context.Context, in *MagicLinkRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_ConsumeMagicLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) CreatePersonalAccessToken(ctx context.Context, in *CreatePersonalAccessTokenRequest, opts ...grpc.CallOption) (*PersonalAccessTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PersonalAccessTokenResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_CreatePersonalAccessToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) ListPersonalAccessTokens(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*ListPersonalAccessTokensResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPersonalAccessTokensResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_ListPersonalAccessTokens_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) RevokePersonalAccessToken(ctx context.Context, in *RevokePersonalAccessTokenRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ChecklistService_RevokePersonalAccessToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) AuthenticatePersonalAccessToken(ctx context.Context, in *AuthenticatePersonalAccessTokenRequest, opts ...grpc.CallOption) (*PersonalAccessTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PersonalAccessTokenResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_AuthenticatePersonalAccessToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) CreateWebAuthnCredential(ctx context.Context, in *CreateWebAuthnCredentialRequest, opts ...grpc.CallOption) (*WebAuthnCredentialResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WebAuthnCredentialResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_CreateWebAuthnCredential_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) ListWebAuthnCredentials(ctx context.Context, in *UserIDRequest, opts ...grpc.CallOption) (*ListWebAuthnCredentialsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebAuthnCredentialsResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_ListWebAuthnCredentials_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) GetWebAuthnCredential(ctx context.Context, in *WebAuthnCredentialIDRequest, opts ...grpc.CallOption) (*WebAuthnCredentialResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WebAuthnCredentialResponse)
+	err := c.cc.Invoke(ctx, ChecklistService_GetWebAuthnCredential_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) UpdateWebAuthnCredentialUsage(ctx context.Context, in *UpdateWebAuthnCredentialUsageRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ChecklistService_UpdateWebAuthnCredentialUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checklistServiceClient) DeleteWebAuthnCredential(ctx context.Context, in *DeleteWebAuthnCredentialRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ChecklistService_DeleteWebAuthnCredential_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ChecklistServiceServer is the server API for ChecklistService service.
 // All implementations must embed UnimplementedChecklistServiceServer
 // for forward compatibility.
@@ -130,180 +571,965 @@ type ChecklistServiceServer interface {
 	CreateUser(context.Context, *UserRequest) (*UserResponse, error)
 	UpdateProfile(context.Context, *UpdateProfileRequest) (*UserResponse, error)
 	GetUserByEmail(context.Context, *EmailRequest) (*UserResponse, error)
+	GetUserByID(context.Context, *UserIDRequest) (*UserResponse, error)
+	UpdateTwoFA(context.Context, *UpdateTwoFARequest) (*UserResponse, error)
+	UpdatePassword(context.Context, *UpdatePasswordRequest) (*Empty, error)
+	RehashPassword(context.Context, *RehashPasswordRequest) (*Empty, error)
+	ScheduleAccountDeletion(context.Context, *ScheduleAccountDeletionRequest) (*Empty, error)
+	CancelAccountDeletion(context.Context, *UserIDRequest) (*CancelAccountDeletionResponse, error)
+	ExportUserData(*UserIDRequest, grpc.ServerStreamingServer[ExportUserDataChunk]) error
+	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	SetUserStatus(context.Context, *SetUserStatusRequest) (*UserResponse, error)
+	ResetTwoFactor(context.Context, *UserIDRequest) (*Empty, error)
+	DeleteUser(context.Context, *UserIDRequest) (*Empty, error)
+	RecordAuditEvent(context.Context, *AuditEventRequest) (*Empty, error)
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
+	ReplaceRecoveryCodes(context.Context, *ReplaceRecoveryCodesRequest) (*Empty, error)
+	UseRecoveryCode(context.Context, *UseRecoveryCodeRequest) (*UseRecoveryCodeResponse, error)
+	UseTOTPStep(context.Context, *UseTOTPStepRequest) (*UseTOTPStepResponse, error)
+	CreateRefreshFamily(context.Context, *CreateRefreshFamilyRequest) (*Empty, error)
+	RotateRefreshToken(context.Context, *RotateRefreshTokenRequest) (*RotateRefreshTokenResponse, error)
+	RevokeRefreshFamily(context.Context, *RevokeRefreshFamilyRequest) (*Empty, error)
+	RevokeUserRefreshFamilies(context.Context, *UserIDRequest) (*Empty, error)
+	ListSessions(context.Context, *UserIDRequest) (*ListSessionsResponse, error)
+	SignInWithIdentity(context.Context, *IdentityRequest) (*UserResponse, error)
+	CreateEmailToken(context.Context, *CreateEmailTokenRequest) (*Empty, error)
+	GetUserByEmailToken(context.Context, *EmailTokenRequest) (*UserResponse, error)
+	ResetPassword(context.Context, *ResetPasswordRequest) (*UserIDResponse, error)
+	VerifyEmail(context.Context, *VerifyEmailRequest) (*UserIDResponse, error)
+	ConsumeMagicLink(context.Context, *MagicLinkRequest) (*UserResponse, error)
+	CreatePersonalAccessToken(context.Context, *CreatePersonalAccessTokenRequest) (*PersonalAccessTokenResponse, error)
+	ListPersonalAccessTokens(context.Context, *UserIDRequest) (*ListPersonalAccessTokensResponse, error)
+	RevokePersonalAccessToken(context.Context, *RevokePersonalAccessTokenRequest) (*Empty, error)
+	AuthenticatePersonalAccessToken(context.Context, *AuthenticatePersonalAccessTokenRequest) (*PersonalAccessTokenResponse, error)
+	CreateWebAuthnCredential(context.Context, *CreateWebAuthnCredentialRequest) (*WebAuthnCredentialResponse, error)
+	ListWebAuthnCredentials(context.Context, *UserIDRequest) (*ListWebAuthnCredentialsResponse, error)
+	GetWebAuthnCredential(context.Context, *WebAuthnCredentialIDRequest) (*WebAuthnCredentialResponse, error)
+	UpdateWebAuthnCredentialUsage(context.Context, *UpdateWebAuthnCredentialUsageRequest) (*Empty, error)
+	DeleteWebAuthnCredential(context.Context, *DeleteWebAuthnCredentialRequest) (*Empty, error)
 	mustEmbedUnimplementedChecklistServiceServer()
 }
 
-// UnimplementedChecklistServiceServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedChecklistServiceServer struct{}
-
-func (UnimplementedChecklistServiceServer) CreateTask(context.Context, *TaskRequest) (*TaskResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateTask not implemented")
+// UnimplementedChecklistServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedChecklistServiceServer struct{}
+
+func (UnimplementedChecklistServiceServer) CreateTask(context.Context, *TaskRequest) (*TaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTask not implemented")
+}
+func (UnimplementedChecklistServiceServer) ListTasks(context.Context, *ListTasksRequest) (*ListTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTasks not implemented")
+}
+func (UnimplementedChecklistServiceServer) DeleteTask(context.Context, *TaskIDRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTask not implemented")
+}
+func (UnimplementedChecklistServiceServer) MarkTaskDone(context.Context, *TaskIDRequest) (*TaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkTaskDone not implemented")
+}
+func (UnimplementedChecklistServiceServer) CreateUser(context.Context, *UserRequest) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedChecklistServiceServer) UpdateProfile(context.Context, *UpdateProfileRequest) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProfile not implemented")
+}
+func (UnimplementedChecklistServiceServer) GetUserByEmail(context.Context, *EmailRequest) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserByEmail not implemented")
+}
+func (UnimplementedChecklistServiceServer) GetUserByID(context.Context, *UserIDRequest) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserByID not implemented")
+}
+func (UnimplementedChecklistServiceServer) UpdateTwoFA(context.Context, *UpdateTwoFARequest) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTwoFA not implemented")
+}
+func (UnimplementedChecklistServiceServer) UpdatePassword(context.Context, *UpdatePasswordRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePassword not implemented")
+}
+func (UnimplementedChecklistServiceServer) RehashPassword(context.Context, *RehashPasswordRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RehashPassword not implemented")
+}
+func (UnimplementedChecklistServiceServer) ScheduleAccountDeletion(context.Context, *ScheduleAccountDeletionRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleAccountDeletion not implemented")
+}
+func (UnimplementedChecklistServiceServer) CancelAccountDeletion(context.Context, *UserIDRequest) (*CancelAccountDeletionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelAccountDeletion not implemented")
+}
+func (UnimplementedChecklistServiceServer) ExportUserData(*UserIDRequest, grpc.ServerStreamingServer[ExportUserDataChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportUserData not implemented")
+}
+func (UnimplementedChecklistServiceServer) ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedChecklistServiceServer) SetUserStatus(context.Context, *SetUserStatusRequest) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserStatus not implemented")
+}
+func (UnimplementedChecklistServiceServer) ResetTwoFactor(context.Context, *UserIDRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetTwoFactor not implemented")
+}
+func (UnimplementedChecklistServiceServer) DeleteUser(context.Context, *UserIDRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedChecklistServiceServer) RecordAuditEvent(context.Context, *AuditEventRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordAuditEvent not implemented")
+}
+func (UnimplementedChecklistServiceServer) ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
+func (UnimplementedChecklistServiceServer) ReplaceRecoveryCodes(context.Context, *ReplaceRecoveryCodesRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplaceRecoveryCodes not implemented")
+}
+func (UnimplementedChecklistServiceServer) UseRecoveryCode(context.Context, *UseRecoveryCodeRequest) (*UseRecoveryCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UseRecoveryCode not implemented")
+}
+func (UnimplementedChecklistServiceServer) UseTOTPStep(context.Context, *UseTOTPStepRequest) (*UseTOTPStepResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UseTOTPStep not implemented")
+}
+func (UnimplementedChecklistServiceServer) CreateRefreshFamily(context.Context, *CreateRefreshFamilyRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRefreshFamily not implemented")
+}
+func (UnimplementedChecklistServiceServer) RotateRefreshToken(context.Context, *RotateRefreshTokenRequest) (*RotateRefreshTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateRefreshToken not implemented")
+}
+func (UnimplementedChecklistServiceServer) RevokeRefreshFamily(context.Context, *RevokeRefreshFamilyRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeRefreshFamily not implemented")
+}
+func (UnimplementedChecklistServiceServer) RevokeUserRefreshFamilies(context.Context, *UserIDRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeUserRefreshFamilies not implemented")
+}
+func (UnimplementedChecklistServiceServer) ListSessions(context.Context, *UserIDRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedChecklistServiceServer) SignInWithIdentity(context.Context, *IdentityRequest) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignInWithIdentity not implemented")
+}
+func (UnimplementedChecklistServiceServer) CreateEmailToken(context.Context, *CreateEmailTokenRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEmailToken not implemented")
+}
+func (UnimplementedChecklistServiceServer) GetUserByEmailToken(context.Context, *EmailTokenRequest) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserByEmailToken not implemented")
+}
+func (UnimplementedChecklistServiceServer) ResetPassword(context.Context, *ResetPasswordRequest) (*UserIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetPassword not implemented")
+}
+func (UnimplementedChecklistServiceServer) VerifyEmail(context.Context, *VerifyEmailRequest) (*UserIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyEmail not implemented")
+}
+func (UnimplementedChecklistServiceServer) ConsumeMagicLink(context.Context, *MagicLinkRequest) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConsumeMagicLink not implemented")
+}
+func (UnimplementedChecklistServiceServer) CreatePersonalAccessToken(context.Context, *CreatePersonalAccessTokenRequest) (*PersonalAccessTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePersonalAccessToken not implemented")
+}
+func (UnimplementedChecklistServiceServer) ListPersonalAccessTokens(context.Context, *UserIDRequest) (*ListPersonalAccessTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPersonalAccessTokens not implemented")
+}
+func (UnimplementedChecklistServiceServer) RevokePersonalAccessToken(context.Context, *RevokePersonalAccessTokenRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokePersonalAccessToken not implemented")
+}
+func (UnimplementedChecklistServiceServer) AuthenticatePersonalAccessToken(context.Context, *AuthenticatePersonalAccessTokenRequest) (*PersonalAccessTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthenticatePersonalAccessToken not implemented")
+}
+func (UnimplementedChecklistServiceServer) CreateWebAuthnCredential(context.Context, *CreateWebAuthnCredentialRequest) (*WebAuthnCredentialResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebAuthnCredential not implemented")
+}
+func (UnimplementedChecklistServiceServer) ListWebAuthnCredentials(context.Context, *UserIDRequest) (*ListWebAuthnCredentialsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebAuthnCredentials not implemented")
+}
+func (UnimplementedChecklistServiceServer) GetWebAuthnCredential(context.Context, *WebAuthnCredentialIDRequest) (*WebAuthnCredentialResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebAuthnCredential not implemented")
+}
+func (UnimplementedChecklistServiceServer) UpdateWebAuthnCredentialUsage(context.Context, *UpdateWebAuthnCredentialUsageRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateWebAuthnCredentialUsage not implemented")
+}
+func (UnimplementedChecklistServiceServer) DeleteWebAuthnCredential(context.Context, *DeleteWebAuthnCredentialRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebAuthnCredential not implemented")
+}
+func (UnimplementedChecklistServiceServer) mustEmbedUnimplementedChecklistServiceServer() {}
+func (UnimplementedChecklistServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeChecklistServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChecklistServiceServer will
+// result in compilation errors.
+type UnsafeChecklistServiceServer interface {
+	mustEmbedUnimplementedChecklistServiceServer()
+}
+
+func RegisterChecklistServiceServer(s grpc.ServiceRegistrar, srv ChecklistServiceServer) {
+	// If the following call pancis, it indicates UnimplementedChecklistServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ChecklistService_ServiceDesc, srv)
+}
+
+func _ChecklistService_CreateTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).CreateTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_CreateTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).CreateTask(ctx, req.(*TaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_ListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).ListTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_ListTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).ListTasks(ctx, req.(*ListTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_DeleteTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).DeleteTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_DeleteTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).DeleteTask(ctx, req.(*TaskIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_MarkTaskDone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).MarkTaskDone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_MarkTaskDone_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).MarkTaskDone(ctx, req.(*TaskIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).CreateUser(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_UpdateProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).UpdateProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_UpdateProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).UpdateProfile(ctx, req.(*UpdateProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_GetUserByEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).GetUserByEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_GetUserByEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).GetUserByEmail(ctx, req.(*EmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_GetUserByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).GetUserByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_GetUserByID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).GetUserByID(ctx, req.(*UserIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_UpdateTwoFA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTwoFARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).UpdateTwoFA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_UpdateTwoFA_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).UpdateTwoFA(ctx, req.(*UpdateTwoFARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_UpdatePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).UpdatePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_UpdatePassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).UpdatePassword(ctx, req.(*UpdatePasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_RehashPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RehashPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).RehashPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_RehashPassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).RehashPassword(ctx, req.(*RehashPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_ScheduleAccountDeletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleAccountDeletionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).ScheduleAccountDeletion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_ScheduleAccountDeletion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).ScheduleAccountDeletion(ctx, req.(*ScheduleAccountDeletionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_CancelAccountDeletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).CancelAccountDeletion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_CancelAccountDeletion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).CancelAccountDeletion(ctx, req.(*UserIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_ExportUserData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(UserIDRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChecklistServiceServer).ExportUserData(m, &grpc.GenericServerStream[UserIDRequest, ExportUserDataChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChecklistService_ExportUserDataServer = grpc.ServerStreamingServer[ExportUserDataChunk]
+
+func _ChecklistService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_ListUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_SetUserStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).SetUserStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_SetUserStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).SetUserStatus(ctx, req.(*SetUserStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_ResetTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).ResetTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_ResetTwoFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).ResetTwoFactor(ctx, req.(*UserIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_DeleteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).DeleteUser(ctx, req.(*UserIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_RecordAuditEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).RecordAuditEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_RecordAuditEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).RecordAuditEvent(ctx, req.(*AuditEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_ListAuditEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).ListAuditEvents(ctx, req.(*ListAuditEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_ReplaceRecoveryCodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaceRecoveryCodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).ReplaceRecoveryCodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_ReplaceRecoveryCodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).ReplaceRecoveryCodes(ctx, req.(*ReplaceRecoveryCodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_UseRecoveryCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UseRecoveryCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).UseRecoveryCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_UseRecoveryCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).UseRecoveryCode(ctx, req.(*UseRecoveryCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_UseTOTPStep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UseTOTPStepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).UseTOTPStep(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_UseTOTPStep_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).UseTOTPStep(ctx, req.(*UseTOTPStepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_CreateRefreshFamily_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRefreshFamilyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).CreateRefreshFamily(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_CreateRefreshFamily_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).CreateRefreshFamily(ctx, req.(*CreateRefreshFamilyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_RotateRefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateRefreshTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).RotateRefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_RotateRefreshToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).RotateRefreshToken(ctx, req.(*RotateRefreshTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_RevokeRefreshFamily_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRefreshFamilyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).RevokeRefreshFamily(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_RevokeRefreshFamily_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).RevokeRefreshFamily(ctx, req.(*RevokeRefreshFamilyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_RevokeUserRefreshFamilies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).RevokeUserRefreshFamilies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_RevokeUserRefreshFamilies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).RevokeUserRefreshFamilies(ctx, req.(*UserIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChecklistService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).ListSessions(ctx, req.(*UserIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedChecklistServiceServer) ListTasks(context.Context, *ListTasksRequest) (*ListTasksResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListTasks not implemented")
+
+func _ChecklistService_SignInWithIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdentityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).SignInWithIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_SignInWithIdentity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).SignInWithIdentity(ctx, req.(*IdentityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedChecklistServiceServer) DeleteTask(context.Context, *TaskIDRequest) (*Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteTask not implemented")
+
+func _ChecklistService_CreateEmailToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEmailTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).CreateEmailToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_CreateEmailToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).CreateEmailToken(ctx, req.(*CreateEmailTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedChecklistServiceServer) MarkTaskDone(context.Context, *TaskIDRequest) (*TaskResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method MarkTaskDone not implemented")
+
+func _ChecklistService_GetUserByEmailToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).GetUserByEmailToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_GetUserByEmailToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).GetUserByEmailToken(ctx, req.(*EmailTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedChecklistServiceServer) CreateUser(context.Context, *UserRequest) (*UserResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+
+func _ChecklistService_ResetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).ResetPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_ResetPassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).ResetPassword(ctx, req.(*ResetPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedChecklistServiceServer) UpdateProfile(context.Context, *UpdateProfileRequest) (*UserResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateProfile not implemented")
+
+func _ChecklistService_VerifyEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).VerifyEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_VerifyEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).VerifyEmail(ctx, req.(*VerifyEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedChecklistServiceServer) GetUserByEmail(context.Context, *EmailRequest) (*UserResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetUserByEmail not implemented")
+
+func _ChecklistService_ConsumeMagicLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MagicLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).ConsumeMagicLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_ConsumeMagicLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).ConsumeMagicLink(ctx, req.(*MagicLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedChecklistServiceServer) mustEmbedUnimplementedChecklistServiceServer() {}
-func (UnimplementedChecklistServiceServer) testEmbeddedByValue()                          {}
 
-// UnsafeChecklistServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to ChecklistServiceServer will
-// result in compilation errors.
-type UnsafeChecklistServiceServer interface {
-	mustEmbedUnimplementedChecklistServiceServer()
+func _ChecklistService_CreatePersonalAccessToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePersonalAccessTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).CreatePersonalAccessToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_CreatePersonalAccessToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).CreatePersonalAccessToken(ctx, req.(*CreatePersonalAccessTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterChecklistServiceServer(s grpc.ServiceRegistrar, srv ChecklistServiceServer) {
-	// If the following call pancis, it indicates UnimplementedChecklistServiceServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func _ChecklistService_ListPersonalAccessTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&ChecklistService_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(ChecklistServiceServer).ListPersonalAccessTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChecklistService_ListPersonalAccessTokens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChecklistServiceServer).ListPersonalAccessTokens(ctx, req.(*UserIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _ChecklistService_CreateTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TaskRequest)
+func _ChecklistService_RevokePersonalAccessToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokePersonalAccessTokenRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChecklistServiceServer).CreateTask(ctx, in)
+		return srv.(ChecklistServiceServer).RevokePersonalAccessToken(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ChecklistService_CreateTask_FullMethodName,
+		FullMethod: ChecklistService_RevokePersonalAccessToken_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChecklistServiceServer).CreateTask(ctx, req.(*TaskRequest))
+		return srv.(ChecklistServiceServer).RevokePersonalAccessToken(ctx, req.(*RevokePersonalAccessTokenRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ChecklistService_ListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListTasksRequest)
+func _ChecklistService_AuthenticatePersonalAccessToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthenticatePersonalAccessTokenRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChecklistServiceServer).ListTasks(ctx, in)
+		return srv.(ChecklistServiceServer).AuthenticatePersonalAccessToken(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ChecklistService_ListTasks_FullMethodName,
+		FullMethod: ChecklistService_AuthenticatePersonalAccessToken_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChecklistServiceServer).ListTasks(ctx, req.(*ListTasksRequest))
+		return srv.(ChecklistServiceServer).AuthenticatePersonalAccessToken(ctx, req.(*AuthenticatePersonalAccessTokenRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ChecklistService_DeleteTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TaskIDRequest)
+func _ChecklistService_CreateWebAuthnCredential_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebAuthnCredentialRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChecklistServiceServer).DeleteTask(ctx, in)
+		return srv.(ChecklistServiceServer).CreateWebAuthnCredential(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ChecklistService_DeleteTask_FullMethodName,
+		FullMethod: ChecklistService_CreateWebAuthnCredential_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChecklistServiceServer).DeleteTask(ctx, req.(*TaskIDRequest))
+		return srv.(ChecklistServiceServer).CreateWebAuthnCredential(ctx, req.(*CreateWebAuthnCredentialRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ChecklistService_MarkTaskDone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TaskIDRequest)
+func _ChecklistService_ListWebAuthnCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChecklistServiceServer).MarkTaskDone(ctx, in)
+		return srv.(ChecklistServiceServer).ListWebAuthnCredentials(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ChecklistService_MarkTaskDone_FullMethodName,
+		FullMethod: ChecklistService_ListWebAuthnCredentials_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChecklistServiceServer).MarkTaskDone(ctx, req.(*TaskIDRequest))
+		return srv.(ChecklistServiceServer).ListWebAuthnCredentials(ctx, req.(*UserIDRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ChecklistService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UserRequest)
+func _ChecklistService_GetWebAuthnCredential_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebAuthnCredentialIDRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChecklistServiceServer).CreateUser(ctx, in)
+		return srv.(ChecklistServiceServer).GetWebAuthnCredential(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ChecklistService_CreateUser_FullMethodName,
+		FullMethod: ChecklistService_GetWebAuthnCredential_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChecklistServiceServer).CreateUser(ctx, req.(*UserRequest))
+		return srv.(ChecklistServiceServer).GetWebAuthnCredential(ctx, req.(*WebAuthnCredentialIDRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ChecklistService_UpdateProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateProfileRequest)
+func _ChecklistService_UpdateWebAuthnCredentialUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateWebAuthnCredentialUsageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChecklistServiceServer).UpdateProfile(ctx, in)
+		return srv.(ChecklistServiceServer).UpdateWebAuthnCredentialUsage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ChecklistService_UpdateProfile_FullMethodName,
+		FullMethod: ChecklistService_UpdateWebAuthnCredentialUsage_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChecklistServiceServer).UpdateProfile(ctx, req.(*UpdateProfileRequest))
+		return srv.(ChecklistServiceServer).UpdateWebAuthnCredentialUsage(ctx, req.(*UpdateWebAuthnCredentialUsageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ChecklistService_GetUserByEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EmailRequest)
+func _ChecklistService_DeleteWebAuthnCredential_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebAuthnCredentialRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChecklistServiceServer).GetUserByEmail(ctx, in)
+		return srv.(ChecklistServiceServer).DeleteWebAuthnCredential(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ChecklistService_GetUserByEmail_FullMethodName,
+		FullMethod: ChecklistService_DeleteWebAuthnCredential_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChecklistServiceServer).GetUserByEmail(ctx, req.(*EmailRequest))
+		return srv.(ChecklistServiceServer).DeleteWebAuthnCredential(ctx, req.(*DeleteWebAuthnCredentialRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -343,7 +1569,153 @@ var ChecklistService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUserByEmail",
 			Handler:    _ChecklistService_GetUserByEmail_Handler,
 		},
+		{
+			MethodName: "GetUserByID",
+			Handler:    _ChecklistService_GetUserByID_Handler,
+		},
+		{
+			MethodName: "UpdateTwoFA",
+			Handler:    _ChecklistService_UpdateTwoFA_Handler,
+		},
+		{
+			MethodName: "UpdatePassword",
+			Handler:    _ChecklistService_UpdatePassword_Handler,
+		},
+		{
+			MethodName: "RehashPassword",
+			Handler:    _ChecklistService_RehashPassword_Handler,
+		},
+		{
+			MethodName: "ScheduleAccountDeletion",
+			Handler:    _ChecklistService_ScheduleAccountDeletion_Handler,
+		},
+		{
+			MethodName: "CancelAccountDeletion",
+			Handler:    _ChecklistService_CancelAccountDeletion_Handler,
+		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _ChecklistService_ListUsers_Handler,
+		},
+		{
+			MethodName: "SetUserStatus",
+			Handler:    _ChecklistService_SetUserStatus_Handler,
+		},
+		{
+			MethodName: "ResetTwoFactor",
+			Handler:    _ChecklistService_ResetTwoFactor_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _ChecklistService_DeleteUser_Handler,
+		},
+		{
+			MethodName: "RecordAuditEvent",
+			Handler:    _ChecklistService_RecordAuditEvent_Handler,
+		},
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _ChecklistService_ListAuditEvents_Handler,
+		},
+		{
+			MethodName: "ReplaceRecoveryCodes",
+			Handler:    _ChecklistService_ReplaceRecoveryCodes_Handler,
+		},
+		{
+			MethodName: "UseRecoveryCode",
+			Handler:    _ChecklistService_UseRecoveryCode_Handler,
+		},
+		{
+			MethodName: "UseTOTPStep",
+			Handler:    _ChecklistService_UseTOTPStep_Handler,
+		},
+		{
+			MethodName: "CreateRefreshFamily",
+			Handler:    _ChecklistService_CreateRefreshFamily_Handler,
+		},
+		{
+			MethodName: "RotateRefreshToken",
+			Handler:    _ChecklistService_RotateRefreshToken_Handler,
+		},
+		{
+			MethodName: "RevokeRefreshFamily",
+			Handler:    _ChecklistService_RevokeRefreshFamily_Handler,
+		},
+		{
+			MethodName: "RevokeUserRefreshFamilies",
+			Handler:    _ChecklistService_RevokeUserRefreshFamilies_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _ChecklistService_ListSessions_Handler,
+		},
+		{
+			MethodName: "SignInWithIdentity",
+			Handler:    _ChecklistService_SignInWithIdentity_Handler,
+		},
+		{
+			MethodName: "CreateEmailToken",
+			Handler:    _ChecklistService_CreateEmailToken_Handler,
+		},
+		{
+			MethodName: "GetUserByEmailToken",
+			Handler:    _ChecklistService_GetUserByEmailToken_Handler,
+		},
+		{
+			MethodName: "ResetPassword",
+			Handler:    _ChecklistService_ResetPassword_Handler,
+		},
+		{
+			MethodName: "VerifyEmail",
+			Handler:    _ChecklistService_VerifyEmail_Handler,
+		},
+		{
+			MethodName: "ConsumeMagicLink",
+			Handler:    _ChecklistService_ConsumeMagicLink_Handler,
+		},
+		{
+			MethodName: "CreatePersonalAccessToken",
+			Handler:    _ChecklistService_CreatePersonalAccessToken_Handler,
+		},
+		{
+			MethodName: "ListPersonalAccessTokens",
+			Handler:    _ChecklistService_ListPersonalAccessTokens_Handler,
+		},
+		{
+			MethodName: "RevokePersonalAccessToken",
+			Handler:    _ChecklistService_RevokePersonalAccessToken_Handler,
+		},
+		{
+			MethodName: "AuthenticatePersonalAccessToken",
+			Handler:    _ChecklistService_AuthenticatePersonalAccessToken_Handler,
+		},
+		{
+			MethodName: "CreateWebAuthnCredential",
+			Handler:    _ChecklistService_CreateWebAuthnCredential_Handler,
+		},
+		{
+			MethodName: "ListWebAuthnCredentials",
+			Handler:    _ChecklistService_ListWebAuthnCredentials_Handler,
+		},
+		{
+			MethodName: "GetWebAuthnCredential",
+			Handler:    _ChecklistService_GetWebAuthnCredential_Handler,
+		},
+		{
+			MethodName: "UpdateWebAuthnCredentialUsage",
+			Handler:    _ChecklistService_UpdateWebAuthnCredentialUsage_Handler,
+		},
+		{
+			MethodName: "DeleteWebAuthnCredential",
+			Handler:    _ChecklistService_DeleteWebAuthnCredential_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportUserData",
+			Handler:       _ChecklistService_ExportUserData_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "checklist.proto",
 }
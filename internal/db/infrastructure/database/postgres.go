@@ -3,9 +3,14 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
+	_ "github.com/lib/pq" // драйвер "postgres" для database/sql
 	"github.com/oziev02/checklist-microservices/internal/db/domain/entities"
+	domainerrors "github.com/oziev02/checklist-microservices/internal/db/domain/errors"
+	"strings"
+	"time"
 )
 
 type PostgresRepository struct {
@@ -48,17 +53,53 @@ func createTables(db *sql.DB) error {
 		return err
 	}
 
+	// Подтверждение email (колонка добавлена после первой версии схемы)
+	_, err = db.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT FALSE`)
+	if err != nil {
+		return err
+	}
+
+	// Роль пользователя: user или admin
+	_, err = db.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'user'`)
+	if err != nil {
+		return err
+	}
+
+	// Запланированное удаление аккаунта: NULL — удаление не запрошено
+	_, err = db.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMPTZ`)
+	if err != nil {
+		return err
+	}
+
+	// Статус аккаунта: active, suspended (отключён администратором) или pending_deletion
+	_, err = db.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active'`)
+	if err != nil {
+		return err
+	}
+
+	// Последний принятый шаг TOTP (RFC 6238): коды этого и более ранних шагов повторно не принимаются
+	_, err = db.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT`)
+	if err != nil {
+		return err
+	}
+
+	// Аккаунты, удаление которых запланировано до появления статуса pending_deletion
+	_, err = db.Exec(`UPDATE users SET status = 'pending_deletion' WHERE status = 'active' AND deletion_scheduled_at IS NOT NULL`)
+	if err != nil {
+		return err
+	}
+
 	// Таблица соцсетей (для хранения socials пользователя)
 	_, err = db.Exec(`
 		CREATE TABLE IF NOT EXISTS user_socials (
-		    user_id UUID PREFERENCES users(id),
+		    user_id UUID REFERENCES users(id),
 		    social_key TEXT,
 		    social_value TEXT,
 		    PRIMARY KEY (user_id, social_key)
 		)
 	`)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	// Таблица задач
@@ -75,6 +116,173 @@ func createTables(db *sql.DB) error {
 		return err
 	}
 
+	// Семейства refresh-токенов: каждая цепочка ротаций от одного входа.
+	// Действителен только токен с current_jti, повтор старого отзывает всё семейство
+	_, err = db.Exec(`
+        CREATE TABLE IF NOT EXISTS refresh_token_families (
+            id UUID PRIMARY KEY,
+            user_id UUID NOT NULL REFERENCES users(id),
+            current_jti UUID NOT NULL,
+            expires_at TIMESTAMPTZ NOT NULL,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            revoked_at TIMESTAMPTZ
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	// Сессии (устройства), по одной на семейство refresh-токенов
+	_, err = db.Exec(`
+        CREATE TABLE IF NOT EXISTS sessions (
+            id UUID PRIMARY KEY REFERENCES refresh_token_families(id),
+            user_id UUID NOT NULL REFERENCES users(id),
+            device_name TEXT,
+            user_agent TEXT,
+            ip TEXT,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	// Учётные записи у OAuth-провайдеров, привязанные к пользователям
+	_, err = db.Exec(`
+        CREATE TABLE IF NOT EXISTS user_identities (
+            provider TEXT NOT NULL,
+            subject TEXT NOT NULL,
+            user_id UUID NOT NULL REFERENCES users(id),
+            email TEXT,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            PRIMARY KEY (provider, subject)
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	// Одноразовые токены из писем (сброс пароля и т.п.), храним только хэши
+	_, err = db.Exec(`
+        CREATE TABLE IF NOT EXISTS email_tokens (
+            token_hash TEXT PRIMARY KEY,
+            user_id UUID NOT NULL REFERENCES users(id),
+            purpose TEXT NOT NULL,
+            expires_at TIMESTAMPTZ NOT NULL,
+            used_at TIMESTAMPTZ,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	// Таблица одноразовых кодов восстановления 2FA (храним только хэши)
+	_, err = db.Exec(`
+        CREATE TABLE IF NOT EXISTS twofa_recovery_codes (
+            id UUID PRIMARY KEY,
+            user_id UUID NOT NULL REFERENCES users(id),
+            code_hash TEXT NOT NULL,
+            used_at TIMESTAMPTZ,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	// Персональные токены доступа (scopes через пробел, как в OAuth)
+	_, err = db.Exec(`
+        CREATE TABLE IF NOT EXISTS personal_access_tokens (
+            id UUID PRIMARY KEY,
+            user_id UUID NOT NULL REFERENCES users(id),
+            name TEXT NOT NULL,
+            token_hash TEXT NOT NULL UNIQUE,
+            scopes TEXT NOT NULL,
+            expires_at TIMESTAMPTZ,
+            last_used_at TIMESTAMPTZ,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            revoked_at TIMESTAMPTZ
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	// Журнал аудита: только добавление записей. Ссылок на users нет, чтобы записи
+	// пережили удаление аккаунта
+	_, err = db.Exec(`
+        CREATE TABLE IF NOT EXISTS audit_events (
+            id UUID PRIMARY KEY,
+            actor_id UUID,
+            action TEXT NOT NULL,
+            target_id UUID,
+            ip TEXT,
+            user_agent TEXT,
+            result TEXT NOT NULL,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
+	// Параметры действия, например фильтры поиска администратора
+	_, err = db.Exec(`ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS details JSONB`)
+	if err != nil {
+		return err
+	}
+
+	// Журнал аудита только дополняется: изменение и удаление записей запрещены
+	_, err = db.Exec(`
+        CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+        BEGIN
+            RAISE EXCEPTION 'audit_events is append-only';
+        END;
+        $$ LANGUAGE plpgsql
+    `)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`
+        CREATE TRIGGER audit_events_append_only
+        BEFORE UPDATE OR DELETE ON audit_events
+        FOR EACH ROW EXECUTE FUNCTION audit_events_append_only()
+    `)
+	if err != nil {
+		return err
+	}
+
+	// История пользователя и действия администратора выбираются по времени
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS audit_events_target_id_idx ON audit_events (target_id, created_at DESC)`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS audit_events_actor_id_idx ON audit_events (actor_id, created_at DESC)`)
+	if err != nil {
+		return err
+	}
+
+	// Passkey (WebAuthn): credential_id — ID ключа в base64url, data — ключ в формате API-сервиса
+	_, err = db.Exec(`
+        CREATE TABLE IF NOT EXISTS webauthn_credentials (
+            credential_id TEXT PRIMARY KEY,
+            user_id UUID NOT NULL REFERENCES users(id),
+            name TEXT NOT NULL,
+            data BYTEA NOT NULL,
+            last_used_at TIMESTAMPTZ,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )
+    `)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -95,140 +303,1234 @@ func (r *PostgresRepository) CreateUser(ctx context.Context, email, password str
 	return user, nil
 }
 
+// UpdateProfile обновляет аватар, описание и соцсети пользователя одной транзакцией.
+// Возвращает nil, если пользователь не найден
 func (r *PostgresRepository) UpdateProfile(ctx context.Context, userID, avatar, description string, socials map[string]string) (*entities.User, error) {
-	// Обновляем основные поля профиля
-	query := `
-		UPDATE users
-		SET avatar = $2, description = $3
-		WHERE id = $1
-		RETURING id, email, password, avatar, twofa_enabled, twofa_secret
-	`
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
 
-	user := &entities.User{}
-	err := r.db.QueryRowContext(ctx, query, userID, avatar, description).Scan(
-		&userID, &user.Email, &user.Password, &user.Avatar, &user.Description, &user.TwoFAEnabled, &user.TwoFASecret,
-	)
+	// Обновляем основные поля профиля
+	result, err := tx.ExecContext(ctx, "UPDATE users SET avatar = $2, description = $3 WHERE id = $1", userID, avatar, description)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to update profile: %v", err)
 	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return nil, nil // Пользователь не найден
+	}
 
 	// Обновляем соцсети
 	// Сначала удаляем старые записи
-	_, err = r.db.ExecContext(ctx, "DELETE FROM user_socials WHERE user_id = $1", userID)
+	_, err = tx.ExecContext(ctx, "DELETE FROM user_socials WHERE user_id = $1", userID)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to delete old socials: %v", err)
 	}
 
 	// Добавляем новые записи
 	for key, value := range socials {
-		_, err = r.db.ExecContext(ctx, "INSERT INTO user_socials (user_id, social_key, social_value) VALUES ($1, $2, $3)", userID, key, value)
+		_, err = tx.ExecContext(ctx, "INSERT INTO user_socials (user_id, social_key, social_value) VALUES ($1, $2, $3)", userID, key, value)
 		if err != nil {
 			return nil, fmt.Errorf("Failed to insert social: %v", err)
 		}
 	}
 
-	user.Socials = socials
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("Failed to commit transaction: %v", err)
+	}
+
+	query := "SELECT " + userColumns + " FROM users WHERE id = $1"
+	user, err := r.scanUser(ctx, r.db.QueryRowContext(ctx, query, userID))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get updated profile: %v", err)
+	}
 	return user, nil
 }
 
 func (r *PostgresRepository) GetUserByEmail(ctx context.Context, email string) (*entities.User, error) {
-	user := &entities.User{}
-	query := `
-        SELECT id, email, password, avatar, description, twofa_enabled, twofa_secret
-        FROM users
-        WHERE email = $1
-    `
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID, &user.Email, &user.Password, &user.Avatar, &user.Description, &user.TwoFAEnabled, &user.TwoFASecret,
-	)
-	if err == sql.ErrNoRows {
-		return nil, nil // Пользователь не найден
-	}
+	query := "SELECT " + userColumns + " FROM users WHERE email = $1"
+	user, err := r.scanUser(ctx, r.db.QueryRowContext(ctx, query, email))
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get user by email: %v", err)
 	}
+	return user, nil
+}
 
-	// Получаем соцсети
-	user.Socials = make(map[string]string)
-	rows, err := r.db.QueryContext(ctx, "SELECT social_key, social_value FROM user_socials WHERE user_id = $1", user.ID)
+func (r *PostgresRepository) GetUserByID(ctx context.Context, userID string) (*entities.User, error) {
+	query := "SELECT " + userColumns + " FROM users WHERE id = $1"
+	user, err := r.scanUser(ctx, r.db.QueryRowContext(ctx, query, userID))
 	if err != nil {
-		return nil, fmt.Errorf("Failed to get socials: %v", err)
+		return nil, fmt.Errorf("Failed to get user by id: %v", err)
 	}
-	defer rows.Close()
+	return user, nil
+}
 
-	for rows.Next() {
-		var key, value string
-		if err := rows.Scan(&key, &value); err != nil {
-			return nil, fmt.Errorf("Failed to scan socials: %v", err)
-		}
-		user.Socials[key] = value
+// UpdateTwoFA сохраняет секрет и флаг двухфакторной аутентификации
+func (r *PostgresRepository) UpdateTwoFA(ctx context.Context, userID string, enabled bool, secret string) (*entities.User, error) {
+	query := `
+        UPDATE users
+        SET twofa_enabled = $2, twofa_secret = $3
+        WHERE id = $1
+        RETURNING ` + userColumns
+	user, err := r.scanUser(ctx, r.db.QueryRowContext(ctx, query, userID, enabled, secret))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update 2FA: %v", err)
 	}
-
 	return user, nil
 }
 
-func (r *PostgresRepository) CreateTask(ctx context.Context, title, content, userID string) (*entities.Task, error) {
-	task := entities.NewTask(title, content, uuid.MustParse(userID))
+// UseTOTPStep запоминает шаг принятого TOTP-кода. Возвращает false, если код этого
+// или более позднего шага уже был принят
+func (r *PostgresRepository) UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
 	query := `
-        INSERT INTO tasks (id, title, content, done, user_id)
-        VALUES ($1, $2, $3, $4, $5)
+        UPDATE users
+        SET totp_last_step = $2
+        WHERE id = $1 AND (totp_last_step IS NULL OR totp_last_step < $2)
     `
-	_, err := r.db.ExecContext(ctx, query, task.ID, task.Title, task.Content, task.Done, task.UserID)
+	result, err := r.db.ExecContext(ctx, query, userID, step)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to create task: %v", err)
+		return false, fmt.Errorf("Failed to use TOTP step: %v", err)
 	}
-	return task, nil
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("Failed to get rows affected: %v", err)
+	}
+	return rowsAffected > 0, nil
 }
 
-func (r *PostgresRepository) ListTasks(ctx context.Context, userID string) ([]*entities.Task, error) {
+// UpdatePassword меняет хэш пароля и отзывает все семейства refresh-токенов пользователя,
+// кроме keepSessionID (текущей сессии)
+func (r *PostgresRepository) UpdatePassword(ctx context.Context, userID, password, keepSessionID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET password = $2 WHERE id = $1", userID, password); err != nil {
+		return fmt.Errorf("Failed to update password: %v", err)
+	}
+
 	query := `
-        SELECT id, title, content, done, user_id
-        FROM tasks
-        WHERE user_id = $1
+        UPDATE refresh_token_families
+        SET revoked_at = NOW()
+        WHERE user_id = $1 AND revoked_at IS NULL AND id::text <> $2
     `
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	if _, err := tx.ExecContext(ctx, query, userID, keepSessionID); err != nil {
+		return fmt.Errorf("Failed to revoke refresh token families: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Failed to commit transaction: %v", err)
+	}
+	return nil
+}
+
+// RehashPassword заменяет хэш пароля, пересчитанный с новыми параметрами. Если пароль
+// успели сменить после проверки (хэш уже другой), ничего не делает
+func (r *PostgresRepository) RehashPassword(ctx context.Context, userID, oldPassword, newPassword string) error {
+	query := "UPDATE users SET password = $3 WHERE id = $1 AND password = $2"
+	if _, err := r.db.ExecContext(ctx, query, userID, oldPassword, newPassword); err != nil {
+		return fmt.Errorf("Failed to rehash password: %v", err)
+	}
+	return nil
+}
+
+// userColumns — колонки users в порядке, ожидаемом scanUser
+const userColumns = "id, email, password, avatar, description, twofa_enabled, twofa_secret, email_verified, role, deletion_scheduled_at, status"
+
+// ListUsers ищет пользователей по части email (пустой query — все) и возвращает страницу
+// в порядке email вместе с общим числом найденных
+func (r *PostgresRepository) ListUsers(ctx context.Context, query string, offset, limit int) ([]*entities.User, int, error) {
+	pattern := "%" + escapeLike(query) + "%"
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users WHERE email ILIKE $1", pattern).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("Failed to count users: %v", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT `+userColumns+`
+        FROM users
+        WHERE email ILIKE $1
+        ORDER BY email
+        OFFSET $2 LIMIT $3
+    `, pattern, offset, limit)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to list tasks: %v", err)
+		return nil, 0, fmt.Errorf("Failed to list users: %v", err)
 	}
 	defer rows.Close()
 
-	var tasks []*entities.Task
+	var users []*entities.User
 	for rows.Next() {
-		task := &entities.Task{}
-		if err := rows.Scan(&task.ID, &task.Title, &task.Content, &task.Done, &task.UserID); err != nil {
-			return nil, fmt.Errorf("Failed to scan task: %v", err)
+		user, err := r.scanUser(ctx, rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("Failed to scan user: %v", err)
 		}
-		tasks = append(tasks, task)
+		users = append(users, user)
 	}
 
-	return tasks, nil
+	return users, total, nil
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE, чтобы строка поиска совпадала буквально
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
 }
 
-func (r *PostgresRepository) DeleteTask(ctx context.Context, taskID string) error {
-	query := "DELETE FROM tasks WHERE id = $1"
-	result, err := r.db.ExecContext(ctx, query, taskID)
+// SetUserStatus меняет статус аккаунта. Включённый аккаунт с запланированным удалением
+// получает статус pending_deletion. Возвращает nil, если пользователь не найден
+func (r *PostgresRepository) SetUserStatus(ctx context.Context, userID, status string) (*entities.User, error) {
+	query := `
+        UPDATE users
+        SET status = CASE WHEN $2::text = 'active' AND deletion_scheduled_at IS NOT NULL THEN 'pending_deletion' ELSE $2::text END
+        WHERE id = $1
+        RETURNING ` + userColumns
+	user, err := r.scanUser(ctx, r.db.QueryRowContext(ctx, query, userID, status))
 	if err != nil {
-		return fmt.Errorf("Failed to delete task: %v", err)
+		return nil, fmt.Errorf("Failed to set user status: %v", err)
+	}
+	return user, nil
+}
+
+// ResetTwoFactor отключает все вторые факторы пользователя: TOTP, коды восстановления и passkey.
+// Возвращает false, если пользователь не найден
+func (r *PostgresRepository) ResetTwoFactor(ctx context.Context, userID string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "UPDATE users SET twofa_enabled = FALSE, twofa_secret = '' WHERE id = $1", userID)
+	if err != nil {
+		return false, fmt.Errorf("Failed to disable 2FA: %v", err)
 	}
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("Failed to get rows affected: %v", err)
+		return false, fmt.Errorf("Failed to get rows affected: %v", err)
 	}
 	if rowsAffected == 0 {
-		return sql.ErrNoRows
+		return false, nil
 	}
-	return nil
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM twofa_recovery_codes WHERE user_id = $1", userID); err != nil {
+		return false, fmt.Errorf("Failed to delete recovery codes: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM webauthn_credentials WHERE user_id = $1", userID); err != nil {
+		return false, fmt.Errorf("Failed to delete webauthn credentials: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("Failed to commit transaction: %v", err)
+	}
+	return true, nil
 }
 
-func (r *PostgresRepository) MarkTaskDone(ctx context.Context, taskID string) (*entities.Task, error) {
+// ScheduleAccountDeletion планирует удаление аккаунта на deleteAt и завершает все его сессии.
+// Возвращает false, если пользователь не найден
+func (r *PostgresRepository) ScheduleAccountDeletion(ctx context.Context, userID string, deleteAt time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// Отключённый администратором аккаунт остаётся отключённым до удаления
 	query := `
-        UPDATE tasks
-        SET done = TRUE
+        UPDATE users
+        SET deletion_scheduled_at = $2,
+            status = CASE WHEN status = 'active' THEN 'pending_deletion' ELSE status END
         WHERE id = $1
+    `
+	result, err := tx.ExecContext(ctx, query, userID, deleteAt)
+	if err != nil {
+		return false, fmt.Errorf("Failed to schedule account deletion: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("Failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	query = `
+        UPDATE refresh_token_families
+        SET revoked_at = NOW()
+        WHERE user_id = $1 AND revoked_at IS NULL
+    `
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return false, fmt.Errorf("Failed to revoke refresh token families: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("Failed to commit transaction: %v", err)
+	}
+	return true, nil
+}
+
+// CancelAccountDeletion отменяет запланированное удаление. Возвращает false, если удаление не было запланировано
+func (r *PostgresRepository) CancelAccountDeletion(ctx context.Context, userID string) (bool, error) {
+	query := `
+        UPDATE users
+        SET deletion_scheduled_at = NULL,
+            status = CASE WHEN status = 'pending_deletion' THEN 'active' ELSE status END
+        WHERE id = $1 AND deletion_scheduled_at IS NOT NULL
+    `
+	result, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return false, fmt.Errorf("Failed to cancel account deletion: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("Failed to get rows affected: %v", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// PurgeDeletedAccounts удаляет аккаунты, срок отмены удаления которых истёк.
+// Каждый аккаунт удаляется со всеми данными в отдельной транзакции; возвращает число удалённых
+func (r *PostgresRepository) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id FROM users WHERE deletion_scheduled_at <= NOW()")
+	if err != nil {
+		return 0, fmt.Errorf("Failed to list accounts to delete: %v", err)
+	}
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("Failed to scan user id: %v", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	rows.Close()
+
+	purged := 0
+	for _, userID := range userIDs {
+		deleted, err := r.purgeAccount(ctx, userID, true)
+		if err != nil {
+			return purged, err
+		}
+		if deleted {
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// Таблицы с данными пользователя в порядке удаления (sessions ссылается на refresh_token_families).
+// Архивы выгрузок данных хранит на диске API-сервис и удаляет их сам, когда удаление
+// аккаунта планируется или выполняется администратором
+var userDataTables = []string{
+	"tasks",
+	"user_socials",
+	"sessions",
+	"refresh_token_families",
+	"user_identities",
+	"email_tokens",
+	"twofa_recovery_codes",
+	"personal_access_tokens",
+	"webauthn_credentials",
+}
+
+// DeleteAccount сразу удаляет пользователя со всеми данными. Возвращает false, если пользователь не найден
+func (r *PostgresRepository) DeleteAccount(ctx context.Context, userID string) (bool, error) {
+	return r.purgeAccount(ctx, userID, false)
+}
+
+// purgeAccount удаляет пользователя и все его данные одной транзакцией. Строка пользователя
+// блокируется; при onlyScheduled удаление проверяется повторно: вход мог отменить его после выборки
+func (r *PostgresRepository) purgeAccount(ctx context.Context, userID string, onlyScheduled bool) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var id string
+	query := "SELECT id FROM users WHERE id = $1 FOR UPDATE"
+	if onlyScheduled {
+		query = "SELECT id FROM users WHERE id = $1 AND deletion_scheduled_at <= NOW() FOR UPDATE"
+	}
+	err = tx.QueryRowContext(ctx, query, userID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("Failed to lock user: %v", err)
+	}
+
+	for _, table := range userDataTables {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE user_id = $1", userID); err != nil {
+			return false, fmt.Errorf("Failed to delete from %s: %v", table, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM users WHERE id = $1", userID); err != nil {
+		return false, fmt.Errorf("Failed to delete user: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("Failed to commit transaction: %v", err)
+	}
+	return true, nil
+}
+
+// scanUser читает пользователя (*sql.Row или *sql.Rows) и его соцсети. Возвращает nil, nil, если пользователь не найден
+func (r *PostgresRepository) scanUser(ctx context.Context, row interface{ Scan(dest ...any) error }) (*entities.User, error) {
+	user := &entities.User{}
+	var deletionScheduledAt sql.NullTime
+	err := row.Scan(
+		&user.ID, &user.Email, &user.Password, &user.Avatar, &user.Description, &user.TwoFAEnabled, &user.TwoFASecret,
+		&user.EmailVerified, &user.Role, &deletionScheduledAt, &user.Status,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil // Пользователь не найден
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deletionScheduledAt.Valid {
+		user.DeletionScheduledAt = &deletionScheduledAt.Time
+	}
+
+	if err := r.loadSocials(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// loadSocials заполняет соцсети пользователя
+func (r *PostgresRepository) loadSocials(ctx context.Context, user *entities.User) error {
+	user.Socials = make(map[string]string)
+	rows, err := r.db.QueryContext(ctx, "SELECT social_key, social_value FROM user_socials WHERE user_id = $1", user.ID)
+	if err != nil {
+		return fmt.Errorf("Failed to get socials: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return fmt.Errorf("Failed to scan socials: %v", err)
+		}
+		user.Socials[key] = value
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes заменяет набор кодов восстановления пользователя новым
+func (r *PostgresRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// Старые коды становятся недействительными
+	if _, err := tx.ExecContext(ctx, "DELETE FROM twofa_recovery_codes WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("Failed to delete old recovery codes: %v", err)
+	}
+
+	for _, hash := range codeHashes {
+		_, err := tx.ExecContext(ctx, "INSERT INTO twofa_recovery_codes (id, user_id, code_hash) VALUES ($1, $2, $3)", uuid.New(), userID, hash)
+		if err != nil {
+			return fmt.Errorf("Failed to insert recovery code: %v", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UseRecoveryCode помечает код использованным. Возвращает false, если код не найден или уже использован
+func (r *PostgresRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	query := `
+        UPDATE twofa_recovery_codes
+        SET used_at = NOW()
+        WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+    `
+	result, err := r.db.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("Failed to use recovery code: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("Failed to get rows affected: %v", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// CreateRefreshFamily заводит новое семейство refresh-токенов и сессию при входе
+func (r *PostgresRepository) CreateRefreshFamily(ctx context.Context, session *entities.Session, jti string, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO refresh_token_families (id, user_id, current_jti, expires_at)
+        VALUES ($1, $2, $3, $4)
+    `
+	if _, err := tx.ExecContext(ctx, query, session.ID, session.UserID, jti, expiresAt); err != nil {
+		return fmt.Errorf("Failed to create refresh token family: %v", err)
+	}
+
+	query = `
+        INSERT INTO sessions (id, user_id, device_name, user_agent, ip)
+        VALUES ($1, $2, $3, $4, $5)
+    `
+	if _, err := tx.ExecContext(ctx, query, session.ID, session.UserID, session.DeviceName, session.UserAgent, session.IP); err != nil {
+		return fmt.Errorf("Failed to create session: %v", err)
+	}
+
+	return tx.Commit()
+}
+
+// RotateRefreshToken заменяет текущий токен семейства новым и возвращает владельца семейства.
+// Если предъявлен уже заменённый токен, семейство отзывается и reused = true
+func (r *PostgresRepository) RotateRefreshToken(ctx context.Context, familyID, oldJTI, newJTI string, expiresAt time.Time) (userID string, reused bool, err error) {
+	query := `
+        UPDATE refresh_token_families
+        SET current_jti = $3, expires_at = $4
+        WHERE id = $1 AND current_jti = $2 AND revoked_at IS NULL AND expires_at > NOW()
+        RETURNING user_id
+    `
+	err = r.db.QueryRowContext(ctx, query, familyID, oldJTI, newJTI, expiresAt).Scan(&userID)
+	if err == nil {
+		if _, err := r.db.ExecContext(ctx, "UPDATE sessions SET last_seen_at = NOW() WHERE id = $1", familyID); err != nil {
+			return "", false, fmt.Errorf("Failed to update session: %v", err)
+		}
+		return userID, false, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", false, fmt.Errorf("Failed to rotate refresh token: %v", err)
+	}
+
+	// Токен не текущий: если семейство ещё активно, значит старый токен использован повторно
+	query = `
+        UPDATE refresh_token_families
+        SET revoked_at = NOW()
+        WHERE id = $1 AND current_jti <> $2 AND revoked_at IS NULL
+    `
+	result, err := r.db.ExecContext(ctx, query, familyID, oldJTI)
+	if err != nil {
+		return "", false, fmt.Errorf("Failed to revoke refresh token family: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return "", false, fmt.Errorf("Failed to get rows affected: %v", err)
+	}
+	return "", rowsAffected > 0, nil
+}
+
+// RevokeRefreshFamily отзывает семейство refresh-токенов пользователя (завершает сессию).
+// Возвращает false, если активного семейства с таким ID у пользователя нет
+func (r *PostgresRepository) RevokeRefreshFamily(ctx context.Context, userID, familyID string) (bool, error) {
+	query := `
+        UPDATE refresh_token_families
+        SET revoked_at = NOW()
+        WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+    `
+	result, err := r.db.ExecContext(ctx, query, familyID, userID)
+	if err != nil {
+		return false, fmt.Errorf("Failed to revoke refresh token family: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("Failed to get rows affected: %v", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// RevokeUserRefreshFamilies отзывает все семейства refresh-токенов пользователя (выход со всех устройств)
+func (r *PostgresRepository) RevokeUserRefreshFamilies(ctx context.Context, userID string) error {
+	query := `
+        UPDATE refresh_token_families
+        SET revoked_at = NOW()
+        WHERE user_id = $1 AND revoked_at IS NULL
+    `
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("Failed to revoke refresh token families: %v", err)
+	}
+	return nil
+}
+
+// ListSessions возвращает активные сессии пользователя, начиная с последней активной
+func (r *PostgresRepository) ListSessions(ctx context.Context, userID string) ([]*entities.Session, error) {
+	query := `
+        SELECT s.id, s.user_id, COALESCE(s.device_name, ''), COALESCE(s.user_agent, ''), COALESCE(s.ip, ''), s.created_at, s.last_seen_at
+        FROM sessions s
+        JOIN refresh_token_families f ON f.id = s.id
+        WHERE s.user_id = $1 AND f.revoked_at IS NULL AND f.expires_at > NOW()
+        ORDER BY s.last_seen_at DESC
+    `
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list sessions: %v", err)
+	}
+	defer rows.Close()
+
+	var sessions []*entities.Session
+	for rows.Next() {
+		session := &entities.Session{}
+		if err := rows.Scan(&session.ID, &session.UserID, &session.DeviceName, &session.UserAgent, &session.IP, &session.CreatedAt, &session.LastSeenAt); err != nil {
+			return nil, fmt.Errorf("Failed to scan session: %v", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// ListSessionHistory возвращает все сессии пользователя, включая завершённые, начиная с новых
+func (r *PostgresRepository) ListSessionHistory(ctx context.Context, userID string) ([]*entities.Session, error) {
+	query := `
+        SELECT s.id, s.user_id, COALESCE(s.device_name, ''), COALESCE(s.user_agent, ''), COALESCE(s.ip, ''), s.created_at, s.last_seen_at, f.revoked_at
+        FROM sessions s
+        JOIN refresh_token_families f ON f.id = s.id
+        WHERE s.user_id = $1
+        ORDER BY s.created_at DESC
+    `
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list session history: %v", err)
+	}
+	defer rows.Close()
+
+	var sessions []*entities.Session
+	for rows.Next() {
+		session := &entities.Session{}
+		var revokedAt sql.NullTime
+		if err := rows.Scan(&session.ID, &session.UserID, &session.DeviceName, &session.UserAgent, &session.IP, &session.CreatedAt, &session.LastSeenAt, &revokedAt); err != nil {
+			return nil, fmt.Errorf("Failed to scan session: %v", err)
+		}
+		if revokedAt.Valid {
+			session.RevokedAt = &revokedAt.Time
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// personalAccessTokenColumns — колонки personal_access_tokens в порядке, ожидаемом scanPersonalAccessToken
+const personalAccessTokenColumns = "id, user_id, name, scopes, expires_at, last_used_at, created_at"
+
+// CreatePersonalAccessToken сохраняет персональный токен доступа по хэшу
+func (r *PostgresRepository) CreatePersonalAccessToken(ctx context.Context, token *entities.PersonalAccessToken, tokenHash string) error {
+	query := `
+        INSERT INTO personal_access_tokens (id, user_id, name, token_hash, scopes, expires_at, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+    `
+	_, err := r.db.ExecContext(ctx, query, token.ID, token.UserID, token.Name, tokenHash, strings.Join(token.Scopes, " "), token.ExpiresAt, token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("Failed to create personal access token: %v", err)
+	}
+	return nil
+}
+
+// ListPersonalAccessTokens возвращает неотозванные токены пользователя, начиная с новых
+func (r *PostgresRepository) ListPersonalAccessTokens(ctx context.Context, userID string) ([]*entities.PersonalAccessToken, error) {
+	query := `
+        SELECT ` + personalAccessTokenColumns + `
+        FROM personal_access_tokens
+        WHERE user_id = $1 AND revoked_at IS NULL
+        ORDER BY created_at DESC
+    `
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list personal access tokens: %v", err)
+	}
+	defer rows.Close()
+
+	var tokens []*entities.PersonalAccessToken
+	for rows.Next() {
+		token, err := scanPersonalAccessToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan personal access token: %v", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+// RevokePersonalAccessToken отзывает токен пользователя. Возвращает false, если токен не найден
+func (r *PostgresRepository) RevokePersonalAccessToken(ctx context.Context, userID, tokenID string) (bool, error) {
+	query := `
+        UPDATE personal_access_tokens
+        SET revoked_at = NOW()
+        WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+    `
+	result, err := r.db.ExecContext(ctx, query, tokenID, userID)
+	if err != nil {
+		return false, fmt.Errorf("Failed to revoke personal access token: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("Failed to get rows affected: %v", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// AuthenticatePersonalAccessToken находит действующий токен по хэшу; при recordUse отмечает
+// время использования. Возвращает nil, если токен не найден, отозван, истёк или аккаунт не активен
+func (r *PostgresRepository) AuthenticatePersonalAccessToken(ctx context.Context, tokenHash string, recordUse bool) (*entities.PersonalAccessToken, error) {
+	const valid = `token_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+            AND user_id IN (SELECT id FROM users WHERE status = 'active')`
+	query := "SELECT " + personalAccessTokenColumns + " FROM personal_access_tokens WHERE " + valid
+	if recordUse {
+		query = `
+        UPDATE personal_access_tokens
+        SET last_used_at = NOW()
+        WHERE ` + valid + `
+        RETURNING ` + personalAccessTokenColumns
+	}
+	token, err := scanPersonalAccessToken(r.db.QueryRowContext(ctx, query, tokenHash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to authenticate personal access token: %v", err)
+	}
+	return token, nil
+}
+
+// scanPersonalAccessToken читает строку personal_access_tokens (*sql.Row или *sql.Rows)
+func scanPersonalAccessToken(row interface{ Scan(dest ...any) error }) (*entities.PersonalAccessToken, error) {
+	token := &entities.PersonalAccessToken{}
+	var scopes string
+	var expiresAt, lastUsedAt sql.NullTime
+	if err := row.Scan(&token.ID, &token.UserID, &token.Name, &scopes, &expiresAt, &lastUsedAt, &token.CreatedAt); err != nil {
+		return nil, err
+	}
+	token.Scopes = strings.Fields(scopes)
+	if expiresAt.Valid {
+		token.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	return token, nil
+}
+
+// RecordAuditEvent добавляет запись в журнал аудита
+func (r *PostgresRepository) RecordAuditEvent(ctx context.Context, event *entities.AuditEvent) error {
+	query := `
+        INSERT INTO audit_events (id, actor_id, action, target_id, ip, user_agent, result, details, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+    `
+	// JSONB передаётся строкой: []byte драйвер отправил бы как bytea
+	var details sql.NullString
+	if len(event.Details) > 0 {
+		encoded, err := json.Marshal(event.Details)
+		if err != nil {
+			return fmt.Errorf("Failed to encode audit event details: %v", err)
+		}
+		details = sql.NullString{String: string(encoded), Valid: true}
+	}
+	_, err := r.db.ExecContext(ctx, query, event.ID, event.ActorID, event.Action, event.TargetID, event.IP, event.UserAgent, event.Result, details, event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("Failed to record audit event: %v", err)
+	}
+	return nil
+}
+
+// ListAuditEvents возвращает страницу записей журнала аудита, новые первыми, и общее число найденных
+func (r *PostgresRepository) ListAuditEvents(ctx context.Context, filter entities.AuditEventFilter, offset, limit int) ([]*entities.AuditEvent, int, error) {
+	var conditions []string
+	var args []any
+	addCondition := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.ActorID != "" {
+		addCondition("actor_id = $%d", filter.ActorID)
+	}
+	if filter.TargetID != "" {
+		addCondition("target_id = $%d", filter.TargetID)
+	}
+	if filter.Action != "" {
+		addCondition("action = $%d", filter.Action)
+	}
+	if filter.Result != "" {
+		addCondition("result = $%d", filter.Result)
+	}
+	if !filter.Since.IsZero() {
+		addCondition("created_at >= $%d", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		addCondition("created_at < $%d", filter.Until)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_events "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("Failed to count audit events: %v", err)
+	}
+
+	query := fmt.Sprintf(`
+        SELECT id, actor_id, action, target_id, ip, user_agent, result, details, created_at
+        FROM audit_events
+        %s
+        ORDER BY created_at DESC, id
+        OFFSET $%d LIMIT $%d
+    `, where, len(args)+1, len(args)+2)
+	rows, err := r.db.QueryContext(ctx, query, append(args, offset, limit)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("Failed to list audit events: %v", err)
+	}
+	defer rows.Close()
+
+	var events []*entities.AuditEvent
+	for rows.Next() {
+		event := &entities.AuditEvent{}
+		var ip, userAgent sql.NullString
+		var details []byte
+		if err := rows.Scan(&event.ID, &event.ActorID, &event.Action, &event.TargetID, &ip, &userAgent, &event.Result, &details, &event.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("Failed to scan audit event: %v", err)
+		}
+		if details != nil {
+			if err := json.Unmarshal(details, &event.Details); err != nil {
+				return nil, 0, fmt.Errorf("Failed to decode audit event details: %v", err)
+			}
+		}
+		event.IP = ip.String
+		event.UserAgent = userAgent.String
+		events = append(events, event)
+	}
+
+	return events, total, nil
+}
+
+// webAuthnCredentialColumns — колонки webauthn_credentials в порядке, ожидаемом scanWebAuthnCredential
+const webAuthnCredentialColumns = "credential_id, user_id, name, data, last_used_at, created_at"
+
+// CreateWebAuthnCredential сохраняет passkey. Возвращает ErrCredentialExists, если ключ уже зарегистрирован
+func (r *PostgresRepository) CreateWebAuthnCredential(ctx context.Context, credential *entities.WebAuthnCredential) error {
+	query := `
+        INSERT INTO webauthn_credentials (credential_id, user_id, name, data, created_at)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (credential_id) DO NOTHING
+    `
+	result, err := r.db.ExecContext(ctx, query, credential.CredentialID, credential.UserID, credential.Name, credential.Data, credential.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("Failed to create webauthn credential: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return domainerrors.ErrCredentialExists
+	}
+	return nil
+}
+
+// ListWebAuthnCredentials возвращает passkey пользователя в порядке регистрации
+func (r *PostgresRepository) ListWebAuthnCredentials(ctx context.Context, userID string) ([]*entities.WebAuthnCredential, error) {
+	query := `
+        SELECT ` + webAuthnCredentialColumns + `
+        FROM webauthn_credentials
+        WHERE user_id = $1
+        ORDER BY created_at
+    `
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list webauthn credentials: %v", err)
+	}
+	defer rows.Close()
+
+	var credentials []*entities.WebAuthnCredential
+	for rows.Next() {
+		credential, err := scanWebAuthnCredential(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan webauthn credential: %v", err)
+		}
+		credentials = append(credentials, credential)
+	}
+
+	return credentials, nil
+}
+
+// GetWebAuthnCredential находит passkey по ID ключа. Возвращает nil, если ключа нет
+func (r *PostgresRepository) GetWebAuthnCredential(ctx context.Context, credentialID string) (*entities.WebAuthnCredential, error) {
+	query := `SELECT ` + webAuthnCredentialColumns + ` FROM webauthn_credentials WHERE credential_id = $1`
+	credential, err := scanWebAuthnCredential(r.db.QueryRowContext(ctx, query, credentialID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get webauthn credential: %v", err)
+	}
+	return credential, nil
+}
+
+// UpdateWebAuthnCredentialUsage сохраняет ключ после входа (новый счётчик подписей) и время использования.
+// Возвращает false, если у пользователя нет такого ключа
+func (r *PostgresRepository) UpdateWebAuthnCredentialUsage(ctx context.Context, userID, credentialID string, data []byte) (bool, error) {
+	query := `
+        UPDATE webauthn_credentials
+        SET data = $3, last_used_at = NOW()
+        WHERE credential_id = $1 AND user_id = $2
+    `
+	result, err := r.db.ExecContext(ctx, query, credentialID, userID, data)
+	if err != nil {
+		return false, fmt.Errorf("Failed to update webauthn credential: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("Failed to get rows affected: %v", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// DeleteWebAuthnCredential удаляет passkey пользователя. Возвращает false, если ключ не найден
+func (r *PostgresRepository) DeleteWebAuthnCredential(ctx context.Context, userID, credentialID string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM webauthn_credentials WHERE credential_id = $1 AND user_id = $2", credentialID, userID)
+	if err != nil {
+		return false, fmt.Errorf("Failed to delete webauthn credential: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("Failed to get rows affected: %v", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// scanWebAuthnCredential читает строку webauthn_credentials (*sql.Row или *sql.Rows)
+func scanWebAuthnCredential(row interface{ Scan(dest ...any) error }) (*entities.WebAuthnCredential, error) {
+	credential := &entities.WebAuthnCredential{}
+	var lastUsedAt sql.NullTime
+	if err := row.Scan(&credential.CredentialID, &credential.UserID, &credential.Name, &credential.Data, &lastUsedAt, &credential.CreatedAt); err != nil {
+		return nil, err
+	}
+	if lastUsedAt.Valid {
+		credential.LastUsedAt = &lastUsedAt.Time
+	}
+	return credential, nil
+}
+
+// ListIdentities возвращает учётные записи OAuth-провайдеров, привязанные к пользователю
+func (r *PostgresRepository) ListIdentities(ctx context.Context, userID string) ([]*entities.Identity, error) {
+	query := `
+        SELECT provider, subject, user_id, COALESCE(email, ''), created_at
+        FROM user_identities
+        WHERE user_id = $1
+        ORDER BY created_at
+    `
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list identities: %v", err)
+	}
+	defer rows.Close()
+
+	var identities []*entities.Identity
+	for rows.Next() {
+		identity := &entities.Identity{}
+		if err := rows.Scan(&identity.Provider, &identity.Subject, &identity.UserID, &identity.Email, &identity.CreatedAt); err != nil {
+			return nil, fmt.Errorf("Failed to scan identity: %v", err)
+		}
+		identities = append(identities, identity)
+	}
+
+	return identities, nil
+}
+
+// SignInWithIdentity находит пользователя по учётной записи провайдера. Если её ещё нет,
+// привязывает к аккаунту с тем же email (только если email подтверждён и у провайдера,
+// и в самом аккаунте) или создаёт новый аккаунт без пароля
+func (r *PostgresRepository) SignInWithIdentity(ctx context.Context, provider, subject, email string, emailVerified bool) (*entities.User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var userID uuid.UUID
+	err = tx.QueryRowContext(ctx, "SELECT user_id FROM user_identities WHERE provider = $1 AND subject = $2", provider, subject).Scan(&userID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("Failed to get identity: %v", err)
+	}
+
+	if err == sql.ErrNoRows {
+		var localEmailVerified bool
+		err = tx.QueryRowContext(ctx, "SELECT id, email_verified FROM users WHERE email = $1", email).Scan(&userID, &localEmailVerified)
+		switch {
+		case err == sql.ErrNoRows:
+			// Новый пользователь: пароля нет, войти можно только через провайдера
+			user := entities.NewUser(email, "")
+			user.EmailVerified = emailVerified
+			query := `
+                INSERT INTO users (id, email, password, avatar, description, twofa_enabled, twofa_secret, email_verified)
+                VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+            `
+			_, err = tx.ExecContext(ctx, query, user.ID, user.Email, user.Password, user.Avatar, user.Description, user.TwoFAEnabled, user.TwoFASecret, user.EmailVerified)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to create user: %v", err)
+			}
+			userID = user.ID
+		case err != nil:
+			return nil, fmt.Errorf("Failed to get user by email: %v", err)
+		case !emailVerified:
+			// Без подтверждения email у провайдера нельзя привязаться к чужому аккаунту
+			return nil, domainerrors.ErrEmailTaken
+		case !localEmailVerified:
+			// Аккаунт с неподтверждённым email мог зарегистрировать кто угодно, заранее задав
+			// свой пароль. Привязка отдала бы владельцу email аккаунт, в который может войти и
+			// злоумышленник, поэтому сначала email нужно подтвердить в самом аккаунте
+			return nil, domainerrors.ErrEmailTaken
+		}
+
+		query := "INSERT INTO user_identities (provider, subject, user_id, email) VALUES ($1, $2, $3, $4)"
+		if _, err := tx.ExecContext(ctx, query, provider, subject, userID, email); err != nil {
+			return nil, fmt.Errorf("Failed to link identity: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("Failed to commit transaction: %v", err)
+	}
+
+	return r.GetUserByID(ctx, userID.String())
+}
+
+// CreateEmailToken сохраняет хэш одноразового токена, отправляемого пользователю по почте
+func (r *PostgresRepository) CreateEmailToken(ctx context.Context, userID, purpose, tokenHash string, expiresAt time.Time) error {
+	query := `
+        INSERT INTO email_tokens (token_hash, user_id, purpose, expires_at)
+        VALUES ($1, $2, $3, $4)
+    `
+	if _, err := r.db.ExecContext(ctx, query, tokenHash, userID, purpose, expiresAt); err != nil {
+		return fmt.Errorf("Failed to create email token: %v", err)
+	}
+	return nil
+}
+
+// ResetPassword по токену сброса устанавливает новый пароль и гасит все токены сброса пользователя.
+// Возвращает ID пользователя или пустую строку, если токен недействителен
+func (r *PostgresRepository) ResetPassword(ctx context.Context, tokenHash, password string) (string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	userID, err := consumeEmailToken(ctx, tx, "password_reset", tokenHash)
+	if err != nil || userID == "" {
+		return "", err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET password = $2 WHERE id = $1", userID, password); err != nil {
+		return "", fmt.Errorf("Failed to update password: %v", err)
+	}
+
+	// Остальные ссылки для сброса, отправленные ранее, больше не действуют
+	query := "UPDATE email_tokens SET used_at = NOW() WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL"
+	if _, err := tx.ExecContext(ctx, query, userID, "password_reset"); err != nil {
+		return "", fmt.Errorf("Failed to invalidate reset tokens: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("Failed to commit transaction: %v", err)
+	}
+	return userID, nil
+}
+
+// VerifyEmail подтверждает email владельца токена. Возвращает ID пользователя
+// или пустую строку, если токен недействителен
+func (r *PostgresRepository) VerifyEmail(ctx context.Context, tokenHash string) (string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	userID, err := consumeEmailToken(ctx, tx, "verify_email", tokenHash)
+	if err != nil || userID == "" {
+		return "", err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET email_verified = TRUE WHERE id = $1", userID); err != nil {
+		return "", fmt.Errorf("Failed to verify email: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("Failed to commit transaction: %v", err)
+	}
+	return userID, nil
+}
+
+// ConsumeMagicLink гасит ссылку для входа и возвращает её владельца. Переход по ссылке
+// подтверждает и email. Остальные ссылки для входа, отправленные ранее, больше не действуют.
+// Возвращает nil, если токен недействителен
+func (r *PostgresRepository) ConsumeMagicLink(ctx context.Context, tokenHash string) (*entities.User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	userID, err := consumeEmailToken(ctx, tx, "magic_link", tokenHash)
+	if err != nil || userID == "" {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET email_verified = TRUE WHERE id = $1", userID); err != nil {
+		return nil, fmt.Errorf("Failed to verify email: %v", err)
+	}
+
+	query := "UPDATE email_tokens SET used_at = NOW() WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL"
+	if _, err := tx.ExecContext(ctx, query, userID, "magic_link"); err != nil {
+		return nil, fmt.Errorf("Failed to invalidate magic link tokens: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("Failed to commit transaction: %v", err)
+	}
+	return r.GetUserByID(ctx, userID)
+}
+
+// GetUserByEmailToken возвращает владельца действующего токена, не погашая его.
+// Возвращает nil, если токен недействителен
+func (r *PostgresRepository) GetUserByEmailToken(ctx context.Context, purpose, tokenHash string) (*entities.User, error) {
+	query := `
+        SELECT user_id FROM email_tokens
+        WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+    `
+	var userID string
+	err := r.db.QueryRowContext(ctx, query, tokenHash, purpose).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get email token: %v", err)
+	}
+	return r.GetUserByID(ctx, userID)
+}
+
+// consumeEmailToken гасит действующий токен с заданным назначением и возвращает ID владельца.
+// Пустая строка — токен не найден, просрочен или уже использован
+func consumeEmailToken(ctx context.Context, tx *sql.Tx, purpose, tokenHash string) (string, error) {
+	query := `
+        UPDATE email_tokens
+        SET used_at = NOW()
+        WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+        RETURNING user_id
+    `
+	var userID string
+	err := tx.QueryRowContext(ctx, query, tokenHash, purpose).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("Failed to consume email token: %v", err)
+	}
+	return userID, nil
+}
+
+func (r *PostgresRepository) CreateTask(ctx context.Context, title, content, userID string) (*entities.Task, error) {
+	task := entities.NewTask(title, content, uuid.MustParse(userID))
+	query := `
+        INSERT INTO tasks (id, title, content, done, user_id)
+        VALUES ($1, $2, $3, $4, $5)
+    `
+	_, err := r.db.ExecContext(ctx, query, task.ID, task.Title, task.Content, task.Done, task.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create task: %v", err)
+	}
+	return task, nil
+}
+
+func (r *PostgresRepository) ListTasks(ctx context.Context, userID string) ([]*entities.Task, error) {
+	query := `
+        SELECT id, title, content, done, user_id
+        FROM tasks
+        WHERE user_id = $1
+    `
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list tasks: %v", err)
+	}
+	defer rows.Close()
+
+	var tasks []*entities.Task
+	for rows.Next() {
+		task := &entities.Task{}
+		if err := rows.Scan(&task.ID, &task.Title, &task.Content, &task.Done, &task.UserID); err != nil {
+			return nil, fmt.Errorf("Failed to scan task: %v", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// EachTask передаёт задачи пользователя в fn по одной, не загружая их все в память
+func (r *PostgresRepository) EachTask(ctx context.Context, userID string, fn func(*entities.Task) error) error {
+	query := `
+        SELECT id, title, content, done, user_id
+        FROM tasks
+        WHERE user_id = $1
+    `
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("Failed to list tasks: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		task := &entities.Task{}
+		if err := rows.Scan(&task.ID, &task.Title, &task.Content, &task.Done, &task.UserID); err != nil {
+			return fmt.Errorf("Failed to scan task: %v", err)
+		}
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// DeleteTask удаляет задачу владельца userID. Возвращает sql.ErrNoRows, если у него нет такой задачи
+func (r *PostgresRepository) DeleteTask(ctx context.Context, userID, taskID string) error {
+	query := "DELETE FROM tasks WHERE id = $1 AND user_id = $2"
+	result, err := r.db.ExecContext(ctx, query, taskID, userID)
+	if err != nil {
+		return fmt.Errorf("Failed to delete task: %v", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// MarkTaskDone отмечает задачу владельца userID выполненной. Возвращает sql.ErrNoRows, если у него нет такой задачи
+func (r *PostgresRepository) MarkTaskDone(ctx context.Context, userID, taskID string) (*entities.Task, error) {
+	query := `
+        UPDATE tasks
+        SET done = TRUE
+        WHERE id = $1 AND user_id = $2
         RETURNING id, title, content, done, user_id
     `
 	task := &entities.Task{}
-	err := r.db.QueryRowContext(ctx, query, taskID).Scan(&task.ID, &task.Title, &task.Content, &task.Done, &task.UserID)
+	err := r.db.QueryRowContext(ctx, query, taskID, userID).Scan(&task.ID, &task.Title, &task.Content, &task.Done, &task.UserID)
 	if err == sql.ErrNoRows {
 		return nil, sql.ErrNoRows
 	}
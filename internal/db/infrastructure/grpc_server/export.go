@@ -0,0 +1,235 @@
+package grpc_server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+	"github.com/oziev02/checklist-microservices/internal/db/domain/entities"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Размер части файла в одном сообщении потока
+const exportChunkSize = 64 * 1024
+
+// Сколько записей журнала аудита читается из БД за один запрос
+const exportAuditEventsPageSize = 500
+
+// exportRepository — данные пользователя, которые читает выгрузка
+type exportRepository interface {
+	GetUserByID(ctx context.Context, userID string) (*entities.User, error)
+	EachTask(ctx context.Context, userID string, fn func(*entities.Task) error) error
+	ListSessionHistory(ctx context.Context, userID string) ([]*entities.Session, error)
+	ListIdentities(ctx context.Context, userID string) ([]*entities.Identity, error)
+	ListPersonalAccessTokens(ctx context.Context, userID string) ([]*entities.PersonalAccessToken, error)
+	ListWebAuthnCredentials(ctx context.Context, userID string) ([]*entities.WebAuthnCredential, error)
+	ListAuditEvents(ctx context.Context, filter entities.AuditEventFilter, offset, limit int) ([]*entities.AuditEvent, int, error)
+}
+
+// exportProfile — профиль пользователя в выгрузке, без хэша пароля и секрета 2FA
+type exportProfile struct {
+	ID                  string     `json:"id"`
+	Email               string     `json:"email"`
+	EmailVerified       bool       `json:"email_verified"`
+	Avatar              string     `json:"avatar"`
+	Description         string     `json:"description"`
+	TwoFAEnabled        bool       `json:"twofa_enabled"`
+	Role                string     `json:"role"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at"`
+}
+
+// exportPasskey — passkey в выгрузке, без открытого ключа и счётчика подписей
+type exportPasskey struct {
+	CredentialID string     `json:"credential_id"`
+	Name         string     `json:"name"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastUsedAt   *time.Time `json:"last_used_at"`
+}
+
+// ExportUserData отправляет все данные пользователя потоком JSON-файлов для архива.
+// Задачи читаются из БД построчно, поэтому размер выгрузки не ограничен памятью сервиса
+func (s *Server) ExportUserData(req *api.UserIDRequest, stream api.ChecklistService_ExportUserDataServer) error {
+	userID, err := requireCaller(stream.Context())
+	if err != nil {
+		return err
+	}
+	return exportUserData(stream, s.repo, userID)
+}
+
+// exportUserData отправляет файлы выгрузки пользователя userID
+func exportUserData(stream api.ChecklistService_ExportUserDataServer, repo exportRepository, userID string) error {
+	ctx := stream.Context()
+	user, err := repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("Failed to get user: %v", err)
+	}
+	if user == nil {
+		return status.Error(codes.NotFound, "user not found")
+	}
+
+	profile := exportProfile{
+		ID:                  user.ID.String(),
+		Email:               user.Email,
+		EmailVerified:       user.EmailVerified,
+		Avatar:              user.Avatar,
+		Description:         user.Description,
+		TwoFAEnabled:        user.TwoFAEnabled,
+		Role:                user.Role,
+		DeletionScheduledAt: user.DeletionScheduledAt,
+	}
+	if err := sendExportJSON(stream, "profile.json", profile); err != nil {
+		return err
+	}
+	if err := sendExportJSON(stream, "socials.json", user.Socials); err != nil {
+		return err
+	}
+
+	if err := sendExportTasks(stream, repo, userID); err != nil {
+		return err
+	}
+
+	sessions, err := repo.ListSessionHistory(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("Failed to list sessions: %v", err)
+	}
+	if err := sendExportJSON(stream, "sessions.json", nonNil(sessions)); err != nil {
+		return err
+	}
+
+	identities, err := repo.ListIdentities(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("Failed to list identities: %v", err)
+	}
+	if err := sendExportJSON(stream, "identities.json", nonNil(identities)); err != nil {
+		return err
+	}
+
+	tokens, err := repo.ListPersonalAccessTokens(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("Failed to list personal access tokens: %v", err)
+	}
+	if err := sendExportJSON(stream, "personal_access_tokens.json", nonNil(tokens)); err != nil {
+		return err
+	}
+
+	credentials, err := repo.ListWebAuthnCredentials(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("Failed to list webauthn credentials: %v", err)
+	}
+	passkeys := make([]exportPasskey, 0, len(credentials))
+	for _, credential := range credentials {
+		passkeys = append(passkeys, exportPasskey{
+			CredentialID: credential.CredentialID,
+			Name:         credential.Name,
+			CreatedAt:    credential.CreatedAt,
+			LastUsedAt:   credential.LastUsedAt,
+		})
+	}
+	if err := sendExportJSON(stream, "passkeys.json", passkeys); err != nil {
+		return err
+	}
+
+	events, err := listExportAuditEvents(ctx, repo, userID)
+	if err != nil {
+		return err
+	}
+	return sendExportJSON(stream, "audit_events.json", nonNil(events))
+}
+
+// listExportAuditEvents читает все записи журнала аудита об аккаунте пользователя.
+// Записи, добавленные во время выгрузки, в неё не попадают, чтобы страницы не сдвигались
+func listExportAuditEvents(ctx context.Context, repo exportRepository, userID string) ([]*entities.AuditEvent, error) {
+	filter := entities.AuditEventFilter{TargetID: userID, Until: time.Now()}
+	var events []*entities.AuditEvent
+	for {
+		page, total, err := repo.ListAuditEvents(ctx, filter, len(events), exportAuditEventsPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list audit events: %v", err)
+		}
+		events = append(events, page...)
+		if len(page) == 0 || len(events) >= total {
+			return events, nil
+		}
+	}
+}
+
+// sendExportTasks отправляет tasks.json, кодируя JSON-массив по мере чтения задач
+func sendExportTasks(stream api.ChecklistService_ExportUserDataServer, repo exportRepository, userID string) error {
+	w := newExportFileWriter(stream, "tasks.json")
+	if _, err := w.WriteString("["); err != nil {
+		return err
+	}
+
+	first := true
+	err := repo.EachTask(stream.Context(), userID, func(task *entities.Task) error {
+		if !first {
+			if _, err := w.WriteString(","); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		data, err := json.Marshal(task)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to export tasks: %v", err)
+	}
+
+	if _, err := w.WriteString("]"); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// sendExportJSON отправляет value как файл name
+func sendExportJSON(stream api.ChecklistService_ExportUserDataServer, name string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to encode %s: %v", name, err)
+	}
+
+	w := newExportFileWriter(stream, name)
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// newExportFileWriter возвращает буферизованную запись в файл name, отправляющую части по exportChunkSize
+func newExportFileWriter(stream api.ChecklistService_ExportUserDataServer, name string) *bufio.Writer {
+	return bufio.NewWriterSize(exportChunkSender{stream: stream, file: name}, exportChunkSize)
+}
+
+type exportChunkSender struct {
+	stream api.ChecklistService_ExportUserDataServer
+	file   string
+}
+
+// Write отправляет p частями не больше exportChunkSize: bufio передаёт крупные записи мимо буфера.
+// Сообщение сериализуется в Send, поэтому буфер bufio можно переиспользовать после возврата
+func (s exportChunkSender) Write(p []byte) (int, error) {
+	for sent := 0; sent < len(p); sent += exportChunkSize {
+		end := min(sent+exportChunkSize, len(p))
+		if err := s.stream.Send(&api.ExportUserDataChunk{File: s.file, Data: p[sent:end]}); err != nil {
+			return sent, err
+		}
+	}
+	return len(p), nil
+}
+
+// nonNil заменяет nil-срез пустым, чтобы в файле был [] вместо null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
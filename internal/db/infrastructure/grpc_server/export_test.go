@@ -0,0 +1,123 @@
+package grpc_server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/grpc_client"
+	"github.com/oziev02/checklist-microservices/internal/db/domain/entities"
+)
+
+// exportTestRepository хранит данные одного пользователя в памяти
+type exportTestRepository struct {
+	user   *entities.User
+	events []*entities.AuditEvent
+}
+
+func (r *exportTestRepository) GetUserByID(ctx context.Context, userID string) (*entities.User, error) {
+	if userID != r.user.ID.String() {
+		return nil, nil
+	}
+	return r.user, nil
+}
+
+func (r *exportTestRepository) EachTask(ctx context.Context, userID string, fn func(*entities.Task) error) error {
+	return nil
+}
+
+func (r *exportTestRepository) ListSessionHistory(ctx context.Context, userID string) ([]*entities.Session, error) {
+	return nil, nil
+}
+
+func (r *exportTestRepository) ListIdentities(ctx context.Context, userID string) ([]*entities.Identity, error) {
+	return nil, nil
+}
+
+func (r *exportTestRepository) ListPersonalAccessTokens(ctx context.Context, userID string) ([]*entities.PersonalAccessToken, error) {
+	return nil, nil
+}
+
+func (r *exportTestRepository) ListWebAuthnCredentials(ctx context.Context, userID string) ([]*entities.WebAuthnCredential, error) {
+	return nil, nil
+}
+
+func (r *exportTestRepository) ListAuditEvents(ctx context.Context, filter entities.AuditEventFilter, offset, limit int) ([]*entities.AuditEvent, int, error) {
+	var matched []*entities.AuditEvent
+	for _, event := range r.events {
+		if filter.TargetID != "" && (event.TargetID == nil || event.TargetID.String() != filter.TargetID) {
+			continue
+		}
+		matched = append(matched, event)
+	}
+	total := len(matched)
+	matched = matched[min(offset, total):min(offset+limit, total)]
+	return matched, total, nil
+}
+
+// exportService выгружает данные из exportTestRepository
+type exportService struct {
+	api.UnimplementedChecklistServiceServer
+	repo exportRepository
+}
+
+func (s exportService) ExportUserData(req *api.UserIDRequest, stream api.ChecklistService_ExportUserDataServer) error {
+	userID, err := requireCaller(stream.Context())
+	if err != nil {
+		return err
+	}
+	return exportUserData(stream, s.repo, userID)
+}
+
+// В архив попадает журнал аудита об аккаунте пользователя, но не о других аккаунтах
+func TestExportUserDataIncludesAuditEvents(t *testing.T) {
+	userID := uuid.MustParse(testUserID)
+	otherID := uuid.MustParse(testOtherID)
+	adminID := uuid.MustParse(testAdminID)
+	repo := &exportTestRepository{
+		user: &entities.User{ID: userID, Email: "user@example.com"},
+		events: []*entities.AuditEvent{
+			{ID: uuid.New(), ActorID: &userID, Action: "auth.login", TargetID: &userID, Result: "success", CreatedAt: time.Now()},
+			{ID: uuid.New(), ActorID: &adminID, Action: "admin.user.disable", TargetID: &userID, Result: "success", CreatedAt: time.Now()},
+			{ID: uuid.New(), ActorID: &otherID, Action: "auth.login", TargetID: &otherID, Result: "success", CreatedAt: time.Now()},
+		},
+	}
+	client := newTestClient(t, exportService{repo: repo})
+
+	stream, err := client.ExportUserData(grpc_client.AsUser(context.Background(), testUserID), testUserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[chunk.File] = append(files[chunk.File], chunk.Data...)
+	}
+
+	data, ok := files["audit_events.json"]
+	if !ok {
+		t.Fatal("audit_events.json is missing from the export")
+	}
+	var events []entities.AuditEvent
+	if err := json.Unmarshal(data, &events); err != nil {
+		t.Fatalf("audit_events.json: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("exported %d audit events, want 2", len(events))
+	}
+	for _, event := range events {
+		if event.TargetID == nil || *event.TargetID != userID {
+			t.Errorf("exported audit event %s about %v, want only events about %s", event.Action, event.TargetID, userID)
+		}
+	}
+}
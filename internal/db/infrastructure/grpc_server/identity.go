@@ -0,0 +1,209 @@
+package grpc_server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Ключи метаданных gRPC с ID пользователя, от имени которого API-сервис выполняет запрос,
+// и ID администратора, выполняющего действие над чужим аккаунтом
+const (
+	userIDMetadataKey  = "x-user-id"
+	adminIDMetadataKey = "x-admin-id"
+)
+
+// unscopedMethods — вызовы, которым пользователь запроса не нужен: вход и поиск пользователя
+// по email, токену или ключу и запись в журнал аудита. Остальные вызовы работают с данными
+// одного пользователя или доступны только администратору и без x-user-id или x-admin-id
+// отклоняются, поэтому новый вызов по умолчанию требует пользователя
+var unscopedMethods = map[string]bool{
+	// Вход и регистрация: пользователь ещё не известен
+	api.ChecklistService_CreateUser_FullMethodName:                      true,
+	api.ChecklistService_GetUserByEmail_FullMethodName:                  true,
+	api.ChecklistService_SignInWithIdentity_FullMethodName:              true,
+	api.ChecklistService_GetUserByEmailToken_FullMethodName:             true,
+	api.ChecklistService_ResetPassword_FullMethodName:                   true,
+	api.ChecklistService_VerifyEmail_FullMethodName:                     true,
+	api.ChecklistService_ConsumeMagicLink_FullMethodName:                true,
+	api.ChecklistService_RotateRefreshToken_FullMethodName:              true,
+	api.ChecklistService_AuthenticatePersonalAccessToken_FullMethodName: true,
+	api.ChecklistService_GetWebAuthnCredential_FullMethodName:           true,
+
+	// Журнал аудита пишется и для неаутентифицированных действий
+	api.ChecklistService_RecordAuditEvent_FullMethodName: true,
+}
+
+// adminMethods — вызовы, которые администратор (x-admin-id) выполняет над любым аккаунтом.
+// true — вызов доступен только администратору, false — и пользователю над своими данными
+var adminMethods = map[string]bool{
+	api.ChecklistService_ListUsers_FullMethodName:      true,
+	api.ChecklistService_SetUserStatus_FullMethodName:  true,
+	api.ChecklistService_ResetTwoFactor_FullMethodName: true,
+	api.ChecklistService_DeleteUser_FullMethodName:     true,
+
+	api.ChecklistService_GetUserByID_FullMethodName:               false,
+	api.ChecklistService_ListSessions_FullMethodName:              false,
+	api.ChecklistService_RevokeUserRefreshFamilies_FullMethodName: false,
+	api.ChecklistService_ListAuditEvents_FullMethodName:           false,
+}
+
+// adminChecker проверяет, что пользователь — действующий администратор
+type adminChecker func(ctx context.Context, userID string) (bool, error)
+
+type callerKey struct{}
+
+type adminKey struct{}
+
+// callerID возвращает ID пользователя, от имени которого выполняется запрос.
+// false — API-сервис не передал пользователя: вызов из unscopedMethods или от администратора
+func callerID(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(callerKey{}).(string)
+	return userID, ok
+}
+
+// adminID возвращает ID проверенного администратора, выполняющего запрос
+func adminID(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(adminKey{}).(string)
+	return userID, ok
+}
+
+// requireCaller возвращает ID пользователя запроса или ошибку Unauthenticated
+func requireCaller(ctx context.Context) (string, error) {
+	userID, ok := callerID(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "caller user id is required")
+	}
+	return userID, nil
+}
+
+// requireUser возвращает пользователя, с данными которого работает запрос: пользователя
+// запроса или, если запрос выполняет администратор, requested из запроса
+func requireUser(ctx context.Context, requested string) (string, error) {
+	if userID, ok := callerID(ctx); ok {
+		return userID, nil
+	}
+	if _, ok := adminID(ctx); ok && requested != "" {
+		return requested, nil
+	}
+	return "", status.Error(codes.Unauthenticated, "caller user id is required")
+}
+
+// withCaller переносит пользователя или администратора из метаданных в контекст.
+// Администратор принимается только для adminMethods и после проверки роли в isAdmin,
+// пользователь — для остальных вызовов, причём user_id запроса, если он есть, должен
+// совпадать с ним. Вызов не из unscopedMethods без того и другого отклоняется
+func withCaller(ctx context.Context, method string, req interface{}, isAdmin adminChecker) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	userIDs := md.Get(userIDMetadataKey)
+	adminIDs := md.Get(adminIDMetadataKey)
+
+	switch {
+	case len(userIDs) > 0 && len(adminIDs) > 0:
+		return nil, status.Error(codes.InvalidArgument, "caller must be either a user or an administrator")
+
+	case len(adminIDs) > 0:
+		if _, ok := adminMethods[method]; !ok {
+			return nil, status.Error(codes.PermissionDenied, "method is not available to administrators")
+		}
+		id, err := parseCallerID(adminIDs)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := isAdmin(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to check administrator: %v", err)
+		}
+		if !ok {
+			return nil, status.Error(codes.PermissionDenied, "caller is not an administrator")
+		}
+		return context.WithValue(ctx, adminKey{}, id), nil
+
+	case len(userIDs) > 0:
+		if adminMethods[method] {
+			return nil, status.Error(codes.PermissionDenied, "administrator is required")
+		}
+		id, err := parseCallerID(userIDs)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkRequestUser(req, id); err != nil {
+			return nil, err
+		}
+		return context.WithValue(ctx, callerKey{}, id), nil
+
+	default:
+		if unscopedMethods[method] {
+			return ctx, nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "caller user id is required")
+	}
+}
+
+// parseCallerID разбирает единственный ID из метаданных
+func parseCallerID(values []string) (string, error) {
+	if len(values) > 1 {
+		return "", status.Error(codes.InvalidArgument, "multiple caller user ids")
+	}
+	id, err := uuid.Parse(values[0])
+	if err != nil {
+		return "", status.Error(codes.InvalidArgument, "invalid caller user id")
+	}
+	return id.String(), nil
+}
+
+// checkRequestUser проверяет, что user_id запроса, если он есть, совпадает с пользователем запроса
+func checkRequestUser(req interface{}, userID string) error {
+	if r, ok := req.(interface{ GetUserId() string }); ok && r.GetUserId() != "" && r.GetUserId() != userID {
+		return status.Error(codes.PermissionDenied, "user id does not match the caller")
+	}
+	return nil
+}
+
+// unaryCallerInterceptor сохраняет пользователя или администратора запроса в контексте обработчика
+func unaryCallerInterceptor(isAdmin adminChecker) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := withCaller(ctx, info.FullMethod, req, isAdmin)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamCallerInterceptor — то же для потоковых вызовов; запрос проверяется при чтении
+func streamCallerInterceptor(isAdmin adminChecker) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := withCaller(ss.Context(), info.FullMethod, nil, isAdmin)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &callerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type callerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *callerStream) Context() context.Context {
+	return s.ctx
+}
+
+// RecvMsg проверяет, что user_id запроса совпадает с пользователем из метаданных
+func (s *callerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if userID, ok := callerID(s.ctx); ok {
+		return checkRequestUser(m, userID)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package grpc_server
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/grpc_client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	testUserID  = "3f6c2a1e-8d4b-4c1a-9f2e-0a1b2c3d4e5f"
+	testOtherID = "7b1d9e2c-4a3f-4e8b-8c6d-1e2f3a4b5c6d"
+	testAdminID = "c4e8a2b6-1d3f-4a5b-9c7d-8e0f1a2b3c4d"
+)
+
+// testIsAdmin считает администратором только testAdminID
+func testIsAdmin(ctx context.Context, userID string) (bool, error) {
+	return userID == testAdminID, nil
+}
+
+func TestWithCaller(t *testing.T) {
+	withMetadata := func(key string, ids ...string) context.Context {
+		md := metadata.MD{}
+		for _, id := range ids {
+			md.Append(key, id)
+		}
+		return metadata.NewIncomingContext(context.Background(), md)
+	}
+	withUser := func(ids ...string) context.Context {
+		return withMetadata(userIDMetadataKey, ids...)
+	}
+	withAdmin := func(ids ...string) context.Context {
+		return withMetadata(adminIDMetadataKey, ids...)
+	}
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		method     string
+		req        interface{}
+		wantCode   codes.Code
+		wantCaller string
+		wantAdmin  string
+	}{
+		{"scoped call", withUser(testUserID), api.ChecklistService_ListSessions_FullMethodName, &api.UserIDRequest{UserId: testUserID}, codes.OK, testUserID, ""},
+		{"scoped call without user_id in request", withUser(testUserID), api.ChecklistService_DeleteTask_FullMethodName, &api.TaskIDRequest{Id: "1"}, codes.OK, testUserID, ""},
+		{"missing caller", context.Background(), api.ChecklistService_ListSessions_FullMethodName, &api.UserIDRequest{UserId: testUserID}, codes.Unauthenticated, "", ""},
+		{"missing caller on unknown method", context.Background(), "/checklist.ChecklistService/NewMethod", nil, codes.Unauthenticated, "", ""},
+		{"another user's data", withUser(testUserID), api.ChecklistService_UpdateTwoFA_FullMethodName, &api.UpdateTwoFARequest{UserId: testOtherID}, codes.PermissionDenied, "", ""},
+		{"invalid caller", withUser("not-a-uuid"), api.ChecklistService_ListTasks_FullMethodName, &api.ListTasksRequest{}, codes.InvalidArgument, "", ""},
+		{"multiple callers", withUser(testUserID, testOtherID), api.ChecklistService_ListTasks_FullMethodName, &api.ListTasksRequest{}, codes.InvalidArgument, "", ""},
+		{"login lookup", context.Background(), api.ChecklistService_GetUserByEmail_FullMethodName, &api.EmailRequest{Email: "a@example.com"}, codes.OK, "", ""},
+		{"own audit history", withUser(testUserID), api.ChecklistService_ListAuditEvents_FullMethodName, &api.ListAuditEventsRequest{TargetId: testUserID}, codes.OK, testUserID, ""},
+
+		{"admin call", withAdmin(testAdminID), api.ChecklistService_SetUserStatus_FullMethodName, &api.SetUserStatusRequest{UserId: testOtherID}, codes.OK, "", testAdminID},
+		{"admin reads another user", withAdmin(testAdminID), api.ChecklistService_GetUserByID_FullMethodName, &api.UserIDRequest{UserId: testOtherID}, codes.OK, "", testAdminID},
+		{"admin call without caller", context.Background(), api.ChecklistService_SetUserStatus_FullMethodName, &api.SetUserStatusRequest{UserId: testOtherID}, codes.Unauthenticated, "", ""},
+		{"admin call by user", withUser(testUserID), api.ChecklistService_SetUserStatus_FullMethodName, &api.SetUserStatusRequest{UserId: testUserID}, codes.PermissionDenied, "", ""},
+		{"admin call by non-admin", withAdmin(testOtherID), api.ChecklistService_SetUserStatus_FullMethodName, &api.SetUserStatusRequest{UserId: testUserID}, codes.PermissionDenied, "", ""},
+		{"admin on user-only method", withAdmin(testAdminID), api.ChecklistService_UpdateTwoFA_FullMethodName, &api.UpdateTwoFARequest{UserId: testOtherID}, codes.PermissionDenied, "", ""},
+		{"user and admin together", metadata.NewIncomingContext(context.Background(), metadata.Pairs(userIDMetadataKey, testUserID, adminIDMetadataKey, testAdminID)),
+			api.ChecklistService_GetUserByID_FullMethodName, &api.UserIDRequest{UserId: testUserID}, codes.InvalidArgument, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := withCaller(tt.ctx, tt.method, tt.req, testIsAdmin)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v (err: %v)", code, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+			caller, ok := callerID(ctx)
+			if caller != tt.wantCaller || ok != (tt.wantCaller != "") {
+				t.Errorf("caller = %q, %v, want %q", caller, ok, tt.wantCaller)
+			}
+			admin, ok := adminID(ctx)
+			if admin != tt.wantAdmin || ok != (tt.wantAdmin != "") {
+				t.Errorf("admin = %q, %v, want %q", admin, ok, tt.wantAdmin)
+			}
+		})
+	}
+}
+
+// Каждый вызов сервиса либо требует пользователя, либо явно перечислен в unscopedMethods
+// или adminMethods
+func TestUnscopedMethodsExist(t *testing.T) {
+	methods := map[string]bool{}
+	for _, method := range api.ChecklistService_ServiceDesc.Methods {
+		methods["/"+api.ChecklistService_ServiceDesc.ServiceName+"/"+method.MethodName] = true
+	}
+	for method := range unscopedMethods {
+		if !methods[method] {
+			t.Errorf("unscoped method %s is not a unary method of the service", method)
+		}
+	}
+	for method := range adminMethods {
+		if !methods[method] {
+			t.Errorf("admin method %s is not a unary method of the service", method)
+		}
+		if unscopedMethods[method] {
+			t.Errorf("admin method %s must not be unscoped", method)
+		}
+	}
+}
+
+// newTestClient запускает service в памяти с проверкой пользователя запроса, как в NewServer,
+// и подключает к нему клиент API-сервиса
+func newTestClient(t *testing.T, service api.ChecklistServiceServer) *grpc_client.Client {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryCallerInterceptor(testIsAdmin)),
+		grpc.ChainStreamInterceptor(streamCallerInterceptor(testIsAdmin)),
+	)
+	api.RegisterChecklistServiceServer(server, service)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := grpc_client.NewClientFromConn(conn)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// identityService отвечает, с данными какого пользователя работает запрос
+type identityService struct {
+	api.UnimplementedChecklistServiceServer
+}
+
+func (identityService) GetUserByID(ctx context.Context, req *api.UserIDRequest) (*api.UserResponse, error) {
+	userID, err := requireUser(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &api.UserResponse{Id: userID}, nil
+}
+
+func (identityService) SetUserStatus(ctx context.Context, req *api.SetUserStatusRequest) (*api.UserResponse, error) {
+	return &api.UserResponse{Id: req.UserId, Status: req.Status}, nil
+}
+
+// Пользователь запроса передаётся клиентом API-сервиса отдельно от аргументов вызова,
+// и БД-сервис сверяет их
+func TestCallerThroughClient(t *testing.T) {
+	client := newTestClient(t, identityService{})
+
+	asUser := grpc_client.AsUser(context.Background(), testUserID)
+	asAdmin := grpc_client.AsAdmin(context.Background(), testAdminID)
+	asNonAdmin := grpc_client.AsAdmin(context.Background(), testOtherID)
+
+	t.Run("GetUserByID", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			ctx      context.Context
+			userID   string
+			wantCode codes.Code
+			wantID   string
+		}{
+			{"own data", asUser, testUserID, codes.OK, testUserID},
+			{"caller differs from user_id", asUser, testOtherID, codes.PermissionDenied, ""},
+			{"no caller", context.Background(), testUserID, codes.Unauthenticated, ""},
+			{"administrator", asAdmin, testOtherID, codes.OK, testOtherID},
+			{"not an administrator", asNonAdmin, testUserID, codes.PermissionDenied, ""},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				resp, err := client.GetUserByID(tt.ctx, tt.userID)
+				if code := status.Code(err); code != tt.wantCode {
+					t.Fatalf("code = %v, want %v (err: %v)", code, tt.wantCode, err)
+				}
+				if err == nil && resp.Id != tt.wantID {
+					t.Errorf("user = %q, want %q", resp.Id, tt.wantID)
+				}
+			})
+		}
+	})
+
+	t.Run("SetUserStatus", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			ctx      context.Context
+			wantCode codes.Code
+		}{
+			{"administrator", asAdmin, codes.OK},
+			{"user on own account", asUser, codes.PermissionDenied},
+			{"not an administrator", asNonAdmin, codes.PermissionDenied},
+			{"no caller", context.Background(), codes.Unauthenticated},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := client.SetUserStatus(tt.ctx, testUserID, "suspended")
+				if code := status.Code(err); code != tt.wantCode {
+					t.Fatalf("code = %v, want %v (err: %v)", code, tt.wantCode, err)
+				}
+			})
+		}
+	})
+}
@@ -2,13 +2,21 @@ package grpc_server
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/oziev02/checklist-microservices/internal/api/infrastructure/api"
+	"github.com/oziev02/checklist-microservices/internal/db/domain/entities"
+	domainerrors "github.com/oziev02/checklist-microservices/internal/db/domain/errors"
 	"github.com/oziev02/checklist-microservices/internal/db/infrastructure/database"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"log"
 	"net"
 	"os"
+	"time"
 )
 
 // Server представляет gRPC-сервер для БД-сервиса
@@ -34,10 +42,23 @@ func NewServer() (*Server, error) {
 		log.Fatalf("Failed to create Postgres repository: %v", err)
 	}
 
-	return &Server{
-		grpcServer: grpc.NewServer(),
-		repo:       repo,
-	}, nil
+	s := &Server{repo: repo}
+	// Пользователь или администратор, от имени которого API-сервис выполняет запрос,
+	// передаётся в метаданных
+	s.grpcServer = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryCallerInterceptor(s.isAdmin)),
+		grpc.ChainStreamInterceptor(streamCallerInterceptor(s.isAdmin)),
+	)
+	return s, nil
+}
+
+// isAdmin проверяет, что пользователь существует, не отключён и имеет роль администратора
+func (s *Server) isAdmin(ctx context.Context, userID string) (bool, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return user != nil && user.Role == entities.RoleAdmin && user.Status == entities.UserStatusActive, nil
 }
 
 // Start запускает gRPC-сервер
@@ -68,8 +89,43 @@ func (s *Server) Stop() {
 	}
 }
 
+// StartAccountPurge периодически удаляет аккаунты, срок отмены удаления которых истёк.
+// Интервал задаётся ACCOUNT_PURGE_INTERVAL (по умолчанию час)
+func (s *Server) StartAccountPurge() {
+	interval := time.Hour
+	if value := os.Getenv("ACCOUNT_PURGE_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Printf("Invalid ACCOUNT_PURGE_INTERVAL %q, using %s", value, interval)
+		} else {
+			interval = parsed
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			purged, err := s.repo.PurgeDeletedAccounts(context.Background())
+			if err != nil {
+				log.Printf("Failed to purge deleted accounts: %v", err)
+			}
+			if purged > 0 {
+				log.Printf("Purged %d deleted accounts", purged)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// Создаёт задачу пользователя запроса
 func (s *Server) CreateTask(ctx context.Context, req *api.TaskRequest) (*api.TaskResponse, error) {
-	task, err := s.repo.CreateTask(ctx, req.Title, req.Content, req.UserId)
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.repo.CreateTask(ctx, req.Title, req.Content, userID)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create task: %v", err)
 	}
@@ -82,8 +138,14 @@ func (s *Server) CreateTask(ctx context.Context, req *api.TaskRequest) (*api.Tas
 	}, nil
 }
 
+// Возвращает задачи пользователя запроса
 func (s *Server) ListTasks(ctx context.Context, req *api.ListTasksRequest) (*api.ListTasksResponse, error) {
-	tasks, err := s.repo.ListTasks(ctx, req.UserId)
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.repo.ListTasks(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to list tasks: %v", err)
 	}
@@ -102,8 +164,21 @@ func (s *Server) ListTasks(ctx context.Context, req *api.ListTasksRequest) (*api
 	return &api.ListTasksResponse{Tasks: taskResponses}, nil
 }
 
+// Удаляет задачу пользователя запроса; чужая задача не отличается от несуществующей
 func (s *Server) DeleteTask(ctx context.Context, req *api.TaskIDRequest) (*api.Empty, error) {
-	if err := s.repo.DeleteTask(ctx, req.Id); err != nil {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(req.Id); err != nil {
+		return nil, status.Error(codes.NotFound, "task not found")
+	}
+
+	err = s.repo.DeleteTask(ctx, userID, req.Id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "task not found")
+	}
+	if err != nil {
 		return nil, fmt.Errorf("Failed to delete task: %v", err)
 	}
 	return &api.Empty{}, nil
@@ -111,7 +186,18 @@ func (s *Server) DeleteTask(ctx context.Context, req *api.TaskIDRequest) (*api.E
 
 // Обрабатывает отметку задачи как выполненной
 func (s *Server) MarkTaskDone(ctx context.Context, req *api.TaskIDRequest) (*api.TaskResponse, error) {
-	task, err := s.repo.MarkTaskDone(ctx, req.Id)
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(req.Id); err != nil {
+		return nil, status.Error(codes.NotFound, "task not found")
+	}
+
+	task, err := s.repo.MarkTaskDone(ctx, userID, req.Id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "task not found")
+	}
 	if err != nil {
 		return nil, fmt.Errorf("Failed to mark task as done: %v", err)
 	}
@@ -129,33 +215,23 @@ func (s *Server) CreateUser(ctx context.Context, req *api.UserRequest) (*api.Use
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create user: %v", err)
 	}
-	return &api.UserResponse{
-		Id:           user.ID.String(),
-		Email:        user.Email,
-		Password:     user.Password,
-		Avatar:       user.Avatar,
-		Description:  user.Description,
-		Socials:      user.Socials,
-		TwofaEnabled: user.TwoFAEnabled,
-		TwofaSecret:  user.TwoFASecret,
-	}, nil
+	return userResponse(user), nil
 }
 
 func (s *Server) UpdateProfile(ctx context.Context, req *api.UpdateProfileRequest) (*api.UserResponse, error) {
-	user, err := s.repo.UpdateProfile(ctx, req.UserId, req.Avatar, req.Description, req.Socials)
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.UpdateProfile(ctx, userID, req.Avatar, req.Description, req.Socials)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to update profile: %v", err)
 	}
-	return &api.UserResponse{
-		Id:           user.ID.String(),
-		Email:        user.Email,
-		Password:     user.Password,
-		Avatar:       user.Avatar,
-		Description:  user.Description,
-		Socials:      user.Socials,
-		TwofaEnabled: user.TwoFAEnabled,
-		TwofaSecret:  user.TwoFASecret,
-	}, nil
+	if user == nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return userResponse(user), nil
 }
 
 func (s *Server) GetUserByEmail(ctx context.Context, req *api.EmailRequest) (*api.UserResponse, error) {
@@ -164,16 +240,719 @@ func (s *Server) GetUserByEmail(ctx context.Context, req *api.EmailRequest) (*ap
 		return nil, fmt.Errorf("Failed to get user by email: %v", err)
 	}
 	if user == nil {
-		return nil, nil // Пользователь не найден
-	}
-	return &api.UserResponse{
-		Id:           user.ID.String(),
-		Email:        user.Email,
-		Password:     user.Password,
-		Avatar:       user.Avatar,
-		Description:  user.Description,
-		Socials:      user.Socials,
-		TwofaEnabled: user.TwoFAEnabled,
-		TwofaSecret:  user.TwoFASecret,
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return userResponse(user), nil
+}
+
+func (s *Server) GetUserByID(ctx context.Context, req *api.UserIDRequest) (*api.UserResponse, error) {
+	userID, err := requireUser(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get user by id: %v", err)
+	}
+	if user == nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return userResponse(user), nil
+}
+
+// Обновляет секрет и флаг 2FA пользователя
+func (s *Server) UpdateTwoFA(ctx context.Context, req *api.UpdateTwoFARequest) (*api.UserResponse, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.UpdateTwoFA(ctx, userID, req.Enabled, req.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update 2FA: %v", err)
+	}
+	if user == nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return userResponse(user), nil
+}
+
+// Отмечает шаг принятого TOTP-кода, чтобы код нельзя было использовать повторно
+func (s *Server) UseTOTPStep(ctx context.Context, req *api.UseTOTPStepRequest) (*api.UseTOTPStepResponse, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.repo.UseTOTPStep(ctx, userID, req.Step)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to use TOTP step: %v", err)
+	}
+	return &api.UseTOTPStepResponse{Used: used}, nil
+}
+
+// Заменяет коды восстановления 2FA
+func (s *Server) ReplaceRecoveryCodes(ctx context.Context, req *api.ReplaceRecoveryCodesRequest) (*api.Empty, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, req.CodeHashes); err != nil {
+		return nil, fmt.Errorf("Failed to replace recovery codes: %v", err)
+	}
+	return &api.Empty{}, nil
+}
+
+// Погашает одноразовый код восстановления 2FA
+func (s *Server) UseRecoveryCode(ctx context.Context, req *api.UseRecoveryCodeRequest) (*api.UseRecoveryCodeResponse, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.repo.UseRecoveryCode(ctx, userID, req.CodeHash)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to use recovery code: %v", err)
+	}
+	return &api.UseRecoveryCodeResponse{Used: used}, nil
+}
+
+// Заводит семейство refresh-токенов и сессию
+func (s *Server) CreateRefreshFamily(ctx context.Context, req *api.CreateRefreshFamilyRequest) (*api.Empty, error) {
+	caller, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	familyID, err := uuid.Parse(req.FamilyId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid family_id")
+	}
+	userID, err := uuid.Parse(caller)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	session := &entities.Session{
+		ID:         familyID,
+		UserID:     userID,
+		DeviceName: req.DeviceName,
+		UserAgent:  req.UserAgent,
+		IP:         req.Ip,
+	}
+	if err := s.repo.CreateRefreshFamily(ctx, session, req.Jti, time.Unix(req.ExpiresAt, 0)); err != nil {
+		return nil, fmt.Errorf("Failed to create refresh token family: %v", err)
+	}
+	return &api.Empty{}, nil
+}
+
+// Ротирует refresh-токен внутри семейства
+func (s *Server) RotateRefreshToken(ctx context.Context, req *api.RotateRefreshTokenRequest) (*api.RotateRefreshTokenResponse, error) {
+	userID, reused, err := s.repo.RotateRefreshToken(ctx, req.FamilyId, req.OldJti, req.NewJti, time.Unix(req.ExpiresAt, 0))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to rotate refresh token: %v", err)
+	}
+	return &api.RotateRefreshTokenResponse{
+		Rotated:       userID != "",
+		ReuseDetected: reused,
+		UserId:        userID,
 	}, nil
 }
+
+// Отзывает одно семейство refresh-токенов (сессию) пользователя
+func (s *Server) RevokeRefreshFamily(ctx context.Context, req *api.RevokeRefreshFamilyRequest) (*api.Empty, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.repo.RevokeRefreshFamily(ctx, userID, req.FamilyId)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to revoke refresh token family: %v", err)
+	}
+	if !revoked {
+		return nil, status.Error(codes.NotFound, "session not found")
+	}
+	return &api.Empty{}, nil
+}
+
+// Возвращает активные сессии пользователя
+func (s *Server) ListSessions(ctx context.Context, req *api.UserIDRequest) (*api.ListSessionsResponse, error) {
+	userID, err := requireUser(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.repo.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list sessions: %v", err)
+	}
+
+	var sessionResponses []*api.SessionResponse
+	for _, session := range sessions {
+		sessionResponses = append(sessionResponses, &api.SessionResponse{
+			Id:         session.ID.String(),
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			Ip:         session.IP,
+			CreatedAt:  session.CreatedAt.Unix(),
+			LastSeenAt: session.LastSeenAt.Unix(),
+		})
+	}
+
+	return &api.ListSessionsResponse{Sessions: sessionResponses}, nil
+}
+
+// Отзывает все семейства refresh-токенов пользователя
+func (s *Server) RevokeUserRefreshFamilies(ctx context.Context, req *api.UserIDRequest) (*api.Empty, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RevokeUserRefreshFamilies(ctx, userID); err != nil {
+		return nil, fmt.Errorf("Failed to revoke refresh token families: %v", err)
+	}
+	return &api.Empty{}, nil
+}
+
+// Находит, привязывает или создаёт пользователя по учётной записи OAuth-провайдера
+func (s *Server) SignInWithIdentity(ctx context.Context, req *api.IdentityRequest) (*api.UserResponse, error) {
+	if req.Provider == "" || req.Subject == "" || req.Email == "" {
+		return nil, status.Error(codes.InvalidArgument, "provider, subject and email are required")
+	}
+
+	user, err := s.repo.SignInWithIdentity(ctx, req.Provider, req.Subject, req.Email, req.EmailVerified)
+	if errors.Is(err, domainerrors.ErrEmailTaken) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to sign in with identity: %v", err)
+	}
+	if user == nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return userResponse(user), nil
+}
+
+// Сохраняет одноразовый токен для письма пользователю
+func (s *Server) CreateEmailToken(ctx context.Context, req *api.CreateEmailTokenRequest) (*api.Empty, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateEmailToken(ctx, userID, req.Purpose, req.TokenHash, time.Unix(req.ExpiresAt, 0)); err != nil {
+		return nil, fmt.Errorf("Failed to create email token: %v", err)
+	}
+	return &api.Empty{}, nil
+}
+
+// Устанавливает новый пароль по токену сброса
+func (s *Server) ResetPassword(ctx context.Context, req *api.ResetPasswordRequest) (*api.UserIDResponse, error) {
+	userID, err := s.repo.ResetPassword(ctx, req.TokenHash, req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to reset password: %v", err)
+	}
+	if userID == "" {
+		return nil, status.Error(codes.NotFound, "invalid or expired reset token")
+	}
+	return &api.UserIDResponse{UserId: userID}, nil
+}
+
+// Меняет пароль и отзывает остальные сессии пользователя
+func (s *Server) UpdatePassword(ctx context.Context, req *api.UpdatePasswordRequest) (*api.Empty, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdatePassword(ctx, userID, req.Password, req.KeepSessionId); err != nil {
+		return nil, fmt.Errorf("Failed to update password: %v", err)
+	}
+	return &api.Empty{}, nil
+}
+
+// Заменяет хэш пароля на пересчитанный с актуальными параметрами
+func (s *Server) RehashPassword(ctx context.Context, req *api.RehashPasswordRequest) (*api.Empty, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RehashPassword(ctx, userID, req.OldPassword, req.NewPassword); err != nil {
+		return nil, fmt.Errorf("Failed to rehash password: %v", err)
+	}
+	return &api.Empty{}, nil
+}
+
+// Планирует удаление аккаунта и завершает его сессии
+func (s *Server) ScheduleAccountDeletion(ctx context.Context, req *api.ScheduleAccountDeletionRequest) (*api.Empty, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DeleteAt == 0 {
+		return nil, status.Error(codes.InvalidArgument, "delete_at is required")
+	}
+
+	scheduled, err := s.repo.ScheduleAccountDeletion(ctx, userID, time.Unix(req.DeleteAt, 0))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to schedule account deletion: %v", err)
+	}
+	if !scheduled {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return &api.Empty{}, nil
+}
+
+// Отменяет запланированное удаление аккаунта
+func (s *Server) CancelAccountDeletion(ctx context.Context, req *api.UserIDRequest) (*api.CancelAccountDeletionResponse, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cancelled, err := s.repo.CancelAccountDeletion(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to cancel account deletion: %v", err)
+	}
+	return &api.CancelAccountDeletionResponse{Cancelled: cancelled}, nil
+}
+
+// Максимальный размер страницы ListUsers
+const maxListUsersLimit = 100
+
+// Ищет пользователей по части email с постраничным выводом
+func (s *Server) ListUsers(ctx context.Context, req *api.ListUsersRequest) (*api.ListUsersResponse, error) {
+	if req.Offset < 0 || req.Limit <= 0 || req.Limit > maxListUsersLimit {
+		return nil, status.Error(codes.InvalidArgument, "offset must not be negative and limit must be between 1 and 100")
+	}
+
+	users, total, err := s.repo.ListUsers(ctx, req.Query, int(req.Offset), int(req.Limit))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list users: %v", err)
+	}
+
+	var userResponses []*api.UserResponse
+	for _, user := range users {
+		userResponses = append(userResponses, userResponse(user))
+	}
+
+	return &api.ListUsersResponse{Users: userResponses, Total: int32(total)}, nil
+}
+
+// Меняет статус аккаунта (отключение и включение администратором)
+func (s *Server) SetUserStatus(ctx context.Context, req *api.SetUserStatusRequest) (*api.UserResponse, error) {
+	if req.Status != entities.UserStatusActive && req.Status != entities.UserStatusSuspended {
+		return nil, status.Error(codes.InvalidArgument, "unknown status")
+	}
+
+	user, err := s.repo.SetUserStatus(ctx, req.UserId, req.Status)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to set user status: %v", err)
+	}
+	if user == nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return userResponse(user), nil
+}
+
+// Отключает TOTP, коды восстановления и passkey пользователя
+func (s *Server) ResetTwoFactor(ctx context.Context, req *api.UserIDRequest) (*api.Empty, error) {
+	reset, err := s.repo.ResetTwoFactor(ctx, req.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to reset 2FA: %v", err)
+	}
+	if !reset {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return &api.Empty{}, nil
+}
+
+// Сразу удаляет пользователя со всеми данными
+func (s *Server) DeleteUser(ctx context.Context, req *api.UserIDRequest) (*api.Empty, error) {
+	deleted, err := s.repo.DeleteAccount(ctx, req.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to delete user: %v", err)
+	}
+	if !deleted {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return &api.Empty{}, nil
+}
+
+// Добавляет запись в журнал аудита
+func (s *Server) RecordAuditEvent(ctx context.Context, req *api.AuditEventRequest) (*api.Empty, error) {
+	if req.Action == "" || (req.Result != entities.AuditResultSuccess && req.Result != entities.AuditResultFailure) {
+		return nil, status.Error(codes.InvalidArgument, "action and a valid result are required")
+	}
+
+	event := &entities.AuditEvent{
+		ID:        uuid.New(),
+		Action:    req.Action,
+		IP:        req.Ip,
+		UserAgent: req.UserAgent,
+		Result:    req.Result,
+		Details:   req.Details,
+		CreatedAt: time.Now(),
+	}
+	var err error
+	if event.ActorID, err = parseOptionalUUID(req.ActorId); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid actor id")
+	}
+	if event.TargetID, err = parseOptionalUUID(req.TargetId); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid target id")
+	}
+
+	if err := s.repo.RecordAuditEvent(ctx, event); err != nil {
+		return nil, fmt.Errorf("Failed to record audit event: %v", err)
+	}
+	return &api.Empty{}, nil
+}
+
+// Максимальный размер страницы ListAuditEvents
+const maxListAuditEventsLimit = 100
+
+// Возвращает записи журнала аудита по фильтрам, новые первыми. Администратору — любые,
+// пользователю запроса — только записи о его аккаунте
+func (s *Server) ListAuditEvents(ctx context.Context, req *api.ListAuditEventsRequest) (*api.ListAuditEventsResponse, error) {
+	if req.Offset < 0 || req.Limit <= 0 || req.Limit > maxListAuditEventsLimit {
+		return nil, status.Error(codes.InvalidArgument, "offset must not be negative and limit must be between 1 and 100")
+	}
+	for _, id := range []string{req.ActorId, req.TargetId} {
+		if _, err := parseOptionalUUID(id); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid user id")
+		}
+	}
+
+	filter := entities.AuditEventFilter{
+		ActorID:  req.ActorId,
+		TargetID: req.TargetId,
+		Action:   req.Action,
+		Result:   req.Result,
+	}
+	// Пользователь видит только записи о своём аккаунте
+	if userID, ok := callerID(ctx); ok {
+		if req.TargetId != "" && req.TargetId != userID {
+			return nil, status.Error(codes.PermissionDenied, "target id does not match the caller")
+		}
+		filter.TargetID = userID
+	}
+	if req.Since != 0 {
+		filter.Since = time.Unix(req.Since, 0)
+	}
+	if req.Until != 0 {
+		filter.Until = time.Unix(req.Until, 0)
+	}
+
+	events, total, err := s.repo.ListAuditEvents(ctx, filter, int(req.Offset), int(req.Limit))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list audit events: %v", err)
+	}
+
+	var eventResponses []*api.AuditEventResponse
+	for _, event := range events {
+		resp := &api.AuditEventResponse{
+			Id:        event.ID.String(),
+			Action:    event.Action,
+			Ip:        event.IP,
+			UserAgent: event.UserAgent,
+			Result:    event.Result,
+			Details:   event.Details,
+			CreatedAt: event.CreatedAt.Unix(),
+		}
+		if event.ActorID != nil {
+			resp.ActorId = event.ActorID.String()
+		}
+		if event.TargetID != nil {
+			resp.TargetId = event.TargetID.String()
+		}
+		eventResponses = append(eventResponses, resp)
+	}
+
+	return &api.ListAuditEventsResponse{Events: eventResponses, Total: int32(total)}, nil
+}
+
+// parseOptionalUUID разбирает UUID; пустая строка даёт nil
+func parseOptionalUUID(value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// Возвращает владельца действующего токена из письма, не погашая токен
+func (s *Server) GetUserByEmailToken(ctx context.Context, req *api.EmailTokenRequest) (*api.UserResponse, error) {
+	user, err := s.repo.GetUserByEmailToken(ctx, req.Purpose, req.TokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get user by email token: %v", err)
+	}
+	if user == nil {
+		return nil, status.Error(codes.NotFound, "invalid or expired token")
+	}
+	return userResponse(user), nil
+}
+
+// Подтверждает email по токену из письма
+func (s *Server) VerifyEmail(ctx context.Context, req *api.VerifyEmailRequest) (*api.UserIDResponse, error) {
+	userID, err := s.repo.VerifyEmail(ctx, req.TokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to verify email: %v", err)
+	}
+	if userID == "" {
+		return nil, status.Error(codes.NotFound, "invalid or expired verification token")
+	}
+	return &api.UserIDResponse{UserId: userID}, nil
+}
+
+// Гасит ссылку для входа из письма и возвращает её владельца
+func (s *Server) ConsumeMagicLink(ctx context.Context, req *api.MagicLinkRequest) (*api.UserResponse, error) {
+	user, err := s.repo.ConsumeMagicLink(ctx, req.TokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to consume magic link: %v", err)
+	}
+	if user == nil {
+		return nil, status.Error(codes.NotFound, "invalid or expired magic link")
+	}
+	return userResponse(user), nil
+}
+
+// Создаёт персональный токен доступа
+func (s *Server) CreatePersonalAccessToken(ctx context.Context, req *api.CreatePersonalAccessTokenRequest) (*api.PersonalAccessTokenResponse, error) {
+	caller, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(caller)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	token := &entities.PersonalAccessToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		CreatedAt: time.Now(),
+	}
+	if req.ExpiresAt != 0 {
+		expiresAt := time.Unix(req.ExpiresAt, 0)
+		token.ExpiresAt = &expiresAt
+	}
+
+	if err := s.repo.CreatePersonalAccessToken(ctx, token, req.TokenHash); err != nil {
+		return nil, fmt.Errorf("Failed to create personal access token: %v", err)
+	}
+	return personalAccessTokenResponse(token), nil
+}
+
+// Возвращает неотозванные персональные токены пользователя
+func (s *Server) ListPersonalAccessTokens(ctx context.Context, req *api.UserIDRequest) (*api.ListPersonalAccessTokensResponse, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.repo.ListPersonalAccessTokens(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list personal access tokens: %v", err)
+	}
+
+	var tokenResponses []*api.PersonalAccessTokenResponse
+	for _, token := range tokens {
+		tokenResponses = append(tokenResponses, personalAccessTokenResponse(token))
+	}
+
+	return &api.ListPersonalAccessTokensResponse{Tokens: tokenResponses}, nil
+}
+
+// Отзывает персональный токен пользователя
+func (s *Server) RevokePersonalAccessToken(ctx context.Context, req *api.RevokePersonalAccessTokenRequest) (*api.Empty, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.repo.RevokePersonalAccessToken(ctx, userID, req.TokenId)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to revoke personal access token: %v", err)
+	}
+	if !revoked {
+		return nil, status.Error(codes.NotFound, "token not found")
+	}
+	return &api.Empty{}, nil
+}
+
+// Проверяет персональный токен по хэшу и, если запрошено, отмечает время использования
+func (s *Server) AuthenticatePersonalAccessToken(ctx context.Context, req *api.AuthenticatePersonalAccessTokenRequest) (*api.PersonalAccessTokenResponse, error) {
+	token, err := s.repo.AuthenticatePersonalAccessToken(ctx, req.TokenHash, req.RecordUse)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to authenticate personal access token: %v", err)
+	}
+	if token == nil {
+		return nil, status.Error(codes.NotFound, "token not found")
+	}
+	return personalAccessTokenResponse(token), nil
+}
+
+// Сохраняет passkey пользователя
+func (s *Server) CreateWebAuthnCredential(ctx context.Context, req *api.CreateWebAuthnCredentialRequest) (*api.WebAuthnCredentialResponse, error) {
+	caller, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(caller)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+	if req.CredentialId == "" || len(req.Data) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "credential_id and data are required")
+	}
+
+	credential := &entities.WebAuthnCredential{
+		CredentialID: req.CredentialId,
+		UserID:       userID,
+		Name:         req.Name,
+		Data:         req.Data,
+		CreatedAt:    time.Now(),
+	}
+
+	err = s.repo.CreateWebAuthnCredential(ctx, credential)
+	if errors.Is(err, domainerrors.ErrCredentialExists) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create webauthn credential: %v", err)
+	}
+	return webAuthnCredentialResponse(credential), nil
+}
+
+// Возвращает passkey пользователя
+func (s *Server) ListWebAuthnCredentials(ctx context.Context, req *api.UserIDRequest) (*api.ListWebAuthnCredentialsResponse, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := s.repo.ListWebAuthnCredentials(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list webauthn credentials: %v", err)
+	}
+
+	var credentialResponses []*api.WebAuthnCredentialResponse
+	for _, credential := range credentials {
+		credentialResponses = append(credentialResponses, webAuthnCredentialResponse(credential))
+	}
+
+	return &api.ListWebAuthnCredentialsResponse{Credentials: credentialResponses}, nil
+}
+
+// Находит passkey по ID ключа (вход без пароля)
+func (s *Server) GetWebAuthnCredential(ctx context.Context, req *api.WebAuthnCredentialIDRequest) (*api.WebAuthnCredentialResponse, error) {
+	credential, err := s.repo.GetWebAuthnCredential(ctx, req.CredentialId)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get webauthn credential: %v", err)
+	}
+	if credential == nil {
+		return nil, status.Error(codes.NotFound, "credential not found")
+	}
+	return webAuthnCredentialResponse(credential), nil
+}
+
+// Сохраняет passkey пользователя после успешного входа
+func (s *Server) UpdateWebAuthnCredentialUsage(ctx context.Context, req *api.UpdateWebAuthnCredentialUsageRequest) (*api.Empty, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateWebAuthnCredentialUsage(ctx, userID, req.CredentialId, req.Data)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update webauthn credential: %v", err)
+	}
+	if !updated {
+		return nil, status.Error(codes.NotFound, "credential not found")
+	}
+	return &api.Empty{}, nil
+}
+
+// Удаляет passkey пользователя
+func (s *Server) DeleteWebAuthnCredential(ctx context.Context, req *api.DeleteWebAuthnCredentialRequest) (*api.Empty, error) {
+	userID, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.repo.DeleteWebAuthnCredential(ctx, userID, req.CredentialId)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to delete webauthn credential: %v", err)
+	}
+	if !deleted {
+		return nil, status.Error(codes.NotFound, "credential not found")
+	}
+	return &api.Empty{}, nil
+}
+
+// webAuthnCredentialResponse преобразует passkey в ответ gRPC (время — Unix-секунды, 0 — не задано)
+func webAuthnCredentialResponse(credential *entities.WebAuthnCredential) *api.WebAuthnCredentialResponse {
+	resp := &api.WebAuthnCredentialResponse{
+		CredentialId: credential.CredentialID,
+		UserId:       credential.UserID.String(),
+		Name:         credential.Name,
+		Data:         credential.Data,
+		CreatedAt:    credential.CreatedAt.Unix(),
+	}
+	if credential.LastUsedAt != nil {
+		resp.LastUsedAt = credential.LastUsedAt.Unix()
+	}
+	return resp
+}
+
+// personalAccessTokenResponse преобразует токен в ответ gRPC (время — Unix-секунды, 0 — не задано)
+func personalAccessTokenResponse(token *entities.PersonalAccessToken) *api.PersonalAccessTokenResponse {
+	resp := &api.PersonalAccessTokenResponse{
+		Id:        token.ID.String(),
+		UserId:    token.UserID.String(),
+		Name:      token.Name,
+		Scopes:    token.Scopes,
+		CreatedAt: token.CreatedAt.Unix(),
+	}
+	if token.ExpiresAt != nil {
+		resp.ExpiresAt = token.ExpiresAt.Unix()
+	}
+	if token.LastUsedAt != nil {
+		resp.LastUsedAt = token.LastUsedAt.Unix()
+	}
+	return resp
+}
+
+// userResponse преобразует сущность пользователя в gRPC-ответ
+func userResponse(user *entities.User) *api.UserResponse {
+	resp := &api.UserResponse{
+		Id:            user.ID.String(),
+		Email:         user.Email,
+		Password:      user.Password,
+		Avatar:        user.Avatar,
+		Description:   user.Description,
+		Socials:       user.Socials,
+		TwofaEnabled:  user.TwoFAEnabled,
+		TwofaSecret:   user.TwoFASecret,
+		EmailVerified: user.EmailVerified,
+		Role:          user.Role,
+		Status:        user.Status,
+	}
+	if user.DeletionScheduledAt != nil {
+		resp.DeletionScheduledAt = user.DeletionScheduledAt.Unix()
+	}
+	return resp
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/oziev02/checklist-microservices/internal/db/domain/entities"
+)
+
+type AuditRepository interface {
+	RecordAuditEvent(ctx context.Context, event *entities.AuditEvent) error
+	ListAuditEvents(ctx context.Context, filter entities.AuditEventFilter, offset, limit int) ([]*entities.AuditEvent, int, error)
+}
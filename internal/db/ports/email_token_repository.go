@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/oziev02/checklist-microservices/internal/db/domain/entities"
+)
+
+type EmailTokenRepository interface {
+	CreateEmailToken(ctx context.Context, userID, purpose, tokenHash string, expiresAt time.Time) error
+	GetUserByEmailToken(ctx context.Context, purpose, tokenHash string) (*entities.User, error)
+	ResetPassword(ctx context.Context, tokenHash, password string) (string, error)
+	VerifyEmail(ctx context.Context, tokenHash string) (string, error)
+	ConsumeMagicLink(ctx context.Context, tokenHash string) (*entities.User, error)
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+	"github.com/oziev02/checklist-microservices/internal/db/domain/entities"
+)
+
+type IdentityRepository interface {
+	SignInWithIdentity(ctx context.Context, provider, subject, email string, emailVerified bool) (*entities.User, error)
+	ListIdentities(ctx context.Context, userID string) ([]*entities.Identity, error)
+}
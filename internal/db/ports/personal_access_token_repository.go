@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/oziev02/checklist-microservices/internal/db/domain/entities"
+)
+
+type PersonalAccessTokenRepository interface {
+	CreatePersonalAccessToken(ctx context.Context, token *entities.PersonalAccessToken, tokenHash string) error
+	ListPersonalAccessTokens(ctx context.Context, userID string) ([]*entities.PersonalAccessToken, error)
+	RevokePersonalAccessToken(ctx context.Context, userID, tokenID string) (bool, error)
+	AuthenticatePersonalAccessToken(ctx context.Context, tokenHash string, recordUse bool) (*entities.PersonalAccessToken, error)
+}
@@ -0,0 +1,8 @@
+package ports
+
+import "context"
+
+type RecoveryCodeRepository interface {
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+	"github.com/oziev02/checklist-microservices/internal/db/domain/entities"
+	"time"
+)
+
+type RefreshTokenRepository interface {
+	CreateRefreshFamily(ctx context.Context, session *entities.Session, jti string, expiresAt time.Time) error
+	RotateRefreshToken(ctx context.Context, familyID, oldJTI, newJTI string, expiresAt time.Time) (userID string, reused bool, err error)
+	RevokeRefreshFamily(ctx context.Context, userID, familyID string) (bool, error)
+	RevokeUserRefreshFamilies(ctx context.Context, userID string) error
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+	"github.com/oziev02/checklist-microservices/internal/db/domain/entities"
+)
+
+type SessionRepository interface {
+	ListSessions(ctx context.Context, userID string) ([]*entities.Session, error)
+	ListSessionHistory(ctx context.Context, userID string) ([]*entities.Session, error)
+}
@@ -8,6 +8,7 @@ import (
 type TaskRepository interface {
 	CreateTask(ctx context.Context, title, content, userID string) (*entities.Task, error)
 	ListTasks(ctx context.Context, userID string) ([]*entities.Task, error)
-	DeleteTask(ctx context.Context, taskID string) error
-	MarkTaskDone(ctx context.Context, taskID string) (*entities.Task, error)
+	EachTask(ctx context.Context, userID string, fn func(*entities.Task) error) error
+	DeleteTask(ctx context.Context, userID, taskID string) error
+	MarkTaskDone(ctx context.Context, userID, taskID string) (*entities.Task, error)
 }
@@ -2,6 +2,8 @@ package ports
 
 import (
 	"context"
+	"time"
+
 	"github.com/oziev02/checklist-microservices/internal/db/domain/entities"
 )
 
@@ -9,4 +11,16 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, email, password string) (*entities.User, error)
 	UpdateProfile(ctx context.Context, userID, avatar, description string, socials map[string]string) (*entities.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*entities.User, error)
+	GetUserByID(ctx context.Context, userID string) (*entities.User, error)
+	UpdateTwoFA(ctx context.Context, userID string, enabled bool, secret string) (*entities.User, error)
+	UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error)
+	UpdatePassword(ctx context.Context, userID, password, keepSessionID string) error
+	RehashPassword(ctx context.Context, userID, oldPassword, newPassword string) error
+	ScheduleAccountDeletion(ctx context.Context, userID string, deleteAt time.Time) (bool, error)
+	CancelAccountDeletion(ctx context.Context, userID string) (bool, error)
+	PurgeDeletedAccounts(ctx context.Context) (int, error)
+	DeleteAccount(ctx context.Context, userID string) (bool, error)
+	ListUsers(ctx context.Context, query string, offset, limit int) ([]*entities.User, int, error)
+	SetUserStatus(ctx context.Context, userID, status string) (*entities.User, error)
+	ResetTwoFactor(ctx context.Context, userID string) (bool, error)
 }
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/oziev02/checklist-microservices/internal/db/domain/entities"
+)
+
+type WebAuthnCredentialRepository interface {
+	CreateWebAuthnCredential(ctx context.Context, credential *entities.WebAuthnCredential) error
+	ListWebAuthnCredentials(ctx context.Context, userID string) ([]*entities.WebAuthnCredential, error)
+	GetWebAuthnCredential(ctx context.Context, credentialID string) (*entities.WebAuthnCredential, error)
+	UpdateWebAuthnCredentialUsage(ctx context.Context, userID, credentialID string, data []byte) (bool, error)
+	DeleteWebAuthnCredential(ctx context.Context, userID, credentialID string) (bool, error)
+}